@@ -1,32 +1,160 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"io/fs"
 	"log"
+	"os"
+	"strconv"
 
 	"github.com/jayasaleh/todo-list/be/internal/config"
 	"github.com/jayasaleh/todo-list/be/internal/database"
+	"github.com/jayasaleh/todo-list/be/internal/migrator"
+	"github.com/jayasaleh/todo-list/be/migrations"
 )
 
+const usage = `Usage: migrate [flags] <command> [args]
+
+Commands:
+  up              Apply all pending migrations
+  down [N]        Roll back the last N migrations (default 1)
+  goto V          Migrate up or down to version V (0 rolls back everything)
+  status          List migrations and whether they are applied
+  version         Print the last applied version
+  baseline V      Mark migrations up to V as applied without running them
+  create NAME     Create a new pair of up/down files
+
+Flags:
+`
+
 func main() {
-	action := flag.String("action", "up", "Migration action: up or down")
+	dir := flag.String("dir", "", "Directory with the up and down migration folders (default: the migrations of DB_DRIVER built into the binary, or ./migrations for create)")
+	action := flag.String("action", "", "Deprecated: same as the command argument")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	args := flag.Args()
+	if len(args) == 0 && *action != "" {
+		args = []string{*action}
+	}
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+
+	// Creating files does not need a database
+	if command == "create" {
+		if len(args) != 2 {
+			log.Fatal("Usage: migrate create NAME")
+		}
+		target := *dir
+		if target == "" {
+			target = "migrations"
+		}
+		up, down, err := migrator.Create(target, args[1])
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		log.Printf("Created %s", up)
+		log.Printf("Created %s", down)
+		return
+	}
+
 	cfg := config.LoadConfig()
 
-	if _, err := database.Connect(cfg); err != nil {
+	db, err := database.Connect(cfg)
+	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	switch *action {
+	var source fs.FS = os.DirFS(*dir)
+	if *dir == "" {
+		if source, err = migrations.ForDriver(cfg.DBDriver); err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+	}
+
+	m, err := migrator.New(db, source)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	ctx := context.Background()
+
+	switch command {
 	case "up":
-		if err := database.AutoMigrate(); err != nil {
-			log.Fatalf("Failed to run database migration: %v", err)
+		count, err := m.Up(ctx)
+		if err != nil {
+			log.Fatalf("Migration up failed after %d migration(s): %v", count, err)
 		}
-		log.Println("Migration up completed successfully")
+		log.Printf("Migration up completed successfully (%d applied)", count)
+
 	case "down":
-		log.Println("Down migration not implemented. Please use SQL files manually.")
+		n := 1
+		if len(args) > 1 {
+			n = parseNumber(args[1])
+		}
+		count, err := m.Down(ctx, n)
+		if err != nil {
+			log.Fatalf("Migration down failed after %d migration(s): %v", count, err)
+		}
+		log.Printf("Migration down completed successfully (%d rolled back)", count)
+
+	case "goto":
+		if len(args) != 2 {
+			log.Fatal("Usage: migrate goto V")
+		}
+		count, err := m.Goto(ctx, parseNumber(args[1]))
+		if err != nil {
+			log.Fatalf("Migration goto failed after %d migration(s): %v", count, err)
+		}
+		log.Printf("Migration goto completed successfully (%d migrated)", count)
+
+	case "baseline":
+		if len(args) != 2 {
+			log.Fatal("Usage: migrate baseline V")
+		}
+		count, err := m.Baseline(ctx, parseNumber(args[1]))
+		if err != nil {
+			log.Fatalf("Migration baseline failed: %v", err)
+		}
+		log.Printf("Marked %d migration(s) as applied", count)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "-"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d  %-9s  %-19s  %s\n", status.Version, status.State, appliedAt, status.Name)
+		}
+
+	case "version":
+		version, err := m.Version(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get migration version: %v", err)
+		}
+		fmt.Println(version)
+
 	default:
-		log.Fatalf("Unknown action: %s. Use 'up' or 'down'", *action)
+		log.Fatalf("Unknown command: %s. Run 'migrate -h' for usage", command)
+	}
+}
+
+func parseNumber(value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid number: %s", value)
 	}
+	return n
 }
@@ -1,22 +1,70 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 
 	"github.com/jayasaleh/todo-list/be/internal/config"
 	"github.com/jayasaleh/todo-list/be/internal/database"
+	"github.com/jayasaleh/todo-list/be/internal/migrator"
+	"github.com/jayasaleh/todo-list/be/internal/notifier"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
 	"github.com/jayasaleh/todo-list/be/internal/router"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/migrations"
 )
 
 func main() {
 	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
 
-	if _, err := database.Connect(cfg); err != nil {
+	db, err := database.Connect(cfg)
+	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	router := router.SetupRouter()
+	// Apply pending migrations before anything uses the database
+	if cfg.MigrateOnStart {
+		source, err := migrations.ForDriver(cfg.DBDriver)
+		if err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+		m, err := migrator.New(db, source)
+		if err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+		count, err := m.Up(context.Background())
+		if errors.Is(err, migrator.ErrSchemaNewer) {
+			log.Fatalf("Refusing to start: %v", err)
+		}
+		if err != nil {
+			log.Fatalf("Failed to run database migration: %v", err)
+		}
+		log.Printf("Database schema is up to date (%d migration(s) applied)", count)
+	}
+
+	// Deliver due-date reminders in the background
+	scheduler := services.NewReminderScheduler(db, notifier.FromConfig(cfg), cfg.ReminderPollInterval)
+	go scheduler.Run(context.Background())
+
+	// Send outgoing webhooks from the outbox in the background
+	dispatcher := services.NewWebhookDispatcher(db, cfg.WebhookPollInterval)
+	go dispatcher.Run(context.Background())
+
+	// Permanently delete old items from the trash in the background
+	purger := services.NewTrashPurger(db, cfg.TrashRetention, cfg.TrashPurgeInterval)
+	go purger.Run(context.Background())
+
+	// Receive real-time events from every server instance (Postgres LISTEN/NOTIFY)
+	if cfg.DBDriver == config.DriverPostgres {
+		go realtime.Listen(context.Background(), cfg.GetDBConnectionString(), realtime.GetHub())
+	}
+
+	router := router.SetupRouter(cfg, router.NewServices(cfg, db))
 
 	port := fmt.Sprintf(":%s", cfg.Port)
 	log.Printf("Server starting on port %s", cfg.Port)
@@ -1,13 +1,26 @@
 package config
 
 import (
+	"errors"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Database drivers supported by DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type Config struct {
+	// DBDriver selects the database: Postgres, or a SQLite file at DBPath
+	DBDriver   string
+	DBPath     string
 	DBHost     string
 	DBPort     string
 	DBUser     string
@@ -15,6 +28,37 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 	Port       string
+
+	// CORSAllowedOrigins are the browser origins allowed to call the API. "*"
+	// allows any origin for REST requests but not for WebSockets.
+	CORSAllowedOrigins []string
+
+	// MigrateOnStart applies pending migrations when the server starts
+	MigrateOnStart bool
+
+	// Auth
+	JWTSecret       string
+	JWTIssuer       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// Reminders
+	ReminderPollInterval time.Duration
+	SMTPHost             string
+	SMTPPort             string
+	SMTPUsername         string
+	SMTPPassword         string
+	SMTPFrom             string
+	ReminderWebhookURL   string
+	// ReminderWebhookSecret signs webhook payloads (X-Signature-256 header)
+	ReminderWebhookSecret string
+
+	// Outgoing webhooks
+	WebhookPollInterval time.Duration
+
+	// Trash: deleted todos and categories are purged after TrashRetention
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
 }
 
 func LoadConfig() *Config {
@@ -22,6 +66,8 @@ func LoadConfig() *Config {
 	_ = godotenv.Load()
 
 	return &Config{
+		DBDriver:   getEnv("DB_DRIVER", DriverPostgres),
+		DBPath:     getEnv("DB_PATH", "todolist.db"),
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUser:     getEnv("DB_USER", "postgres"),
@@ -29,6 +75,29 @@ func LoadConfig() *Config {
 		DBName:     getEnv("DB_NAME", "todolist_db"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 		Port:       getEnv("PORT", "8080"),
+
+		CORSAllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", []string{"*"}),
+
+		MigrateOnStart: getEnvBool("MIGRATE_ON_START", false),
+
+		JWTSecret:       getEnv("JWT_SECRET", ""),
+		JWTIssuer:       getEnv("JWT_ISSUER", "todo-list-api"),
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		ReminderPollInterval:  getEnvDuration("REMINDER_POLL_INTERVAL", time.Minute),
+		SMTPHost:              getEnv("SMTP_HOST", ""),
+		SMTPPort:              getEnv("SMTP_PORT", "587"),
+		SMTPUsername:          getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:              getEnv("SMTP_FROM", "todo-list@localhost"),
+		ReminderWebhookURL:    getEnv("REMINDER_WEBHOOK_URL", ""),
+		ReminderWebhookSecret: getEnv("REMINDER_WEBHOOK_SECRET", ""),
+
+		WebhookPollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+
+		TrashRetention:     time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		TrashPurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
 	}
 }
 
@@ -40,6 +109,72 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// getEnvList reads a comma-separated list
+func getEnvList(key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration for %s: %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid number for %s: %q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return number
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean for %s: %q, using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return enabled
+}
+
+// Validate checks the settings the server cannot run without
+func (c *Config) Validate() error {
+	// A generated key would break every session on restart and differ
+	// between replicas
+	if c.JWTSecret == "" {
+		return errors.New("JWT_SECRET is not set")
+	}
+	return nil
+}
+
 func (c *Config) GetDBConnectionString() string {
 	return "host=" + c.DBHost +
 		" port=" + c.DBPort +
@@ -50,9 +185,21 @@ func (c *Config) GetDBConnectionString() string {
 }
 
 func (c *Config) LogConfig() {
-	log.Printf("DB Host: %s", c.DBHost)
-	log.Printf("DB Port: %s", c.DBPort)
-	log.Printf("DB Name: %s", c.DBName)
-	log.Printf("DB User: %s", c.DBUser)
+	log.Printf("DB Driver: %s", c.DBDriver)
+	if c.DBDriver == DriverSQLite {
+		log.Printf("DB Path: %s", c.DBPath)
+	} else {
+		log.Printf("DB Host: %s", c.DBHost)
+		log.Printf("DB Port: %s", c.DBPort)
+		log.Printf("DB Name: %s", c.DBName)
+		log.Printf("DB User: %s", c.DBUser)
+	}
 	log.Printf("Server Port: %s", c.Port)
+	log.Printf("CORS Allowed Origins: %s", strings.Join(c.CORSAllowedOrigins, ", "))
+	log.Printf("Migrate On Start: %t", c.MigrateOnStart)
+	log.Printf("Access Token TTL: %s", c.AccessTokenTTL)
+	log.Printf("Refresh Token TTL: %s", c.RefreshTokenTTL)
+	log.Printf("Reminder Poll Interval: %s", c.ReminderPollInterval)
+	log.Printf("Webhook Poll Interval: %s", c.WebhookPollInterval)
+	log.Printf("Trash Retention: %s", c.TrashRetention)
 }
@@ -11,44 +11,38 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
-
+// Connect opens the database selected by DB_DRIVER
 func Connect(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode,
-	)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	gormConfig := &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	}
 
-	DB = db
-	log.Println("Database connected successfully")
-
-	return db, nil
-}
-
-func AutoMigrate() error {
-	if DB == nil {
-		return fmt.Errorf("database connection not initialized")
+	var dialector gorm.Dialector
+	switch cfg.DBDriver {
+	case config.DriverPostgres:
+		dialector = postgres.Open(cfg.GetDBConnectionString())
+	case config.DriverSQLite:
+		sqliteDialector, err := openSQLite(cfg.DBPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		dialector = sqliteDialector
+	default:
+		return nil, fmt.Errorf("invalid DB_DRIVER %q. Must be '%s' or '%s'", cfg.DBDriver, config.DriverPostgres, config.DriverSQLite)
 	}
 
-	err := DB.AutoMigrate(
-		&models.Category{},
-		&models.Todo{},
-	)
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
-		return fmt.Errorf("failed to auto migrate: %w", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Println("Database migration completed successfully")
-	return nil
-}
+	// Use the explicit join model for todo tags (cascade constraints, created_at)
+	if err := db.SetupJoinTable(&models.Todo{}, "Tags", &models.TodoTag{}); err != nil {
+		return nil, fmt.Errorf("failed to setup todo tags join table: %w", err)
+	}
 
-func GetDB() *gorm.DB {
-	return DB
+	log.Println("Database connected successfully")
+
+	return db, nil
 }
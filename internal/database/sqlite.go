@@ -0,0 +1,27 @@
+//go:build !nosqlite
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqlitePragmas enable foreign keys (the migrations cascade deletes), let
+// readers work while a write is in progress and wait for locks instead of
+// failing. Transactions take the write lock when they begin, so that two
+// transactions that read before writing cannot deadlock.
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"
+
+// openSQLite opens a SQLite database file with the pure-Go driver
+func openSQLite(path string) (gorm.Dialector, error) {
+	db, err := sql.Open(sqlite.DriverName, path+"?"+sqlitePragmas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	return &sqlite.Dialector{DriverName: sqlite.DriverName, Conn: &utcConnPool{db: db}}, nil
+}
@@ -0,0 +1,15 @@
+//go:build nosqlite
+
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// openSQLite fails in builds with the nosqlite tag, which leave out the
+// SQLite driver to save several MB in the binaries
+func openSQLite(path string) (gorm.Dialector, error) {
+	return nil, errors.New("SQLite support is not included in this build. Build without -tags nosqlite")
+}
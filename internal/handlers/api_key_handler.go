@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Get API Keys
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.GetAPIKeys(middleware.CurrentUserID(c))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	keyResponses := []models.APIKeyResponse{}
+	for _, key := range keys {
+		keyResponses = append(keyResponses, models.ToAPIKeyResponse(key))
+	}
+
+	utils.OK(c, "Successfully fetching api keys", keyResponses)
+}
+
+// Create API Key
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	key, rawKey, err := h.apiKeyService.CreateAPIKey(middleware.CurrentUserID(c), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "API key created successfully. Store the key now, it will not be shown again", models.CreatedAPIKeyResponse{
+		APIKeyResponse: models.ToAPIKeyResponse(*key),
+		Key:            rawKey,
+	})
+}
+
+// Revoke API Key
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid api key ID")
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeAPIKey(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "API key revoked successfully", models.ToAPIKeyResponse(*key))
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type AuthHandler struct {
+	authService *services.AuthService
+}
+
+func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// Register
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	user, err := h.authService.Register(req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "User registered successfully", models.ToUserResponse(*user))
+}
+
+// Login
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	user, tokens, err := h.authService.Login(req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Login successful", toAuthResponse(*user, tokens))
+}
+
+// Refresh Token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	user, tokens, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Token refreshed successfully", toAuthResponse(*user, tokens))
+}
+
+// Logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.RefreshTokenRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	if err := h.authService.Logout(middleware.CurrentUserID(c), req.RefreshToken); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Logout successful", nil)
+}
+
+// Logout All Sessions
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.authService.LogoutAll(middleware.CurrentUserID(c)); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "All sessions logged out successfully", nil)
+}
+
+// Get Current User
+func (h *AuthHandler) Me(c *gin.Context) {
+	user, err := h.authService.GetUserByID(middleware.CurrentUserID(c))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Successfully fetching user", models.ToUserResponse(*user))
+}
+
+func toAuthResponse(user models.User, tokens *services.TokenPair) models.AuthResponse {
+	return models.AuthResponse{
+		TokenType:             "Bearer",
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt,
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt,
+		User:                  models.ToUserResponse(user),
+	}
+}
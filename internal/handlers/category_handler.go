@@ -4,26 +4,38 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
 	"github.com/jayasaleh/todo-list/be/internal/models"
-	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
 	"github.com/jayasaleh/todo-list/be/pkg/utils"
 )
 
 type CategoryHandler struct {
-	categoryService *services.CategoryService
+	categoryService repository.CategoryRepository
 }
 
-func NewCategoryHandler() *CategoryHandler {
+func NewCategoryHandler(categories repository.CategoryRepository) *CategoryHandler {
 	return &CategoryHandler{
-		categoryService: services.NewCategoryService(),
+		categoryService: categories,
 	}
 }
 
 // Get Categories
 func (h *CategoryHandler) GetCategories(c *gin.Context) {
-	categories, err := h.categoryService.GetAllCategories()
+	var workspaceID uint64
+	if value := c.Query("workspace_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequest(c, "Invalid workspace ID")
+			return
+		}
+		workspaceID = id
+	}
+
+	categories, err := h.categoryService.GetAllCategories(middleware.CurrentUserID(c), uint(workspaceID))
 	if err != nil {
-		utils.InternalServerError(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -43,13 +55,9 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 		return
 	}
 
-	category, err := h.categoryService.GetCategoryByID(uint(id))
+	category, err := h.categoryService.GetCategoryByID(middleware.CurrentUserID(c), uint(id))
 	if err != nil {
-		if err.Error() == "category not found" {
-			utils.NotFound(c, err.Error())
-			return
-		}
-		utils.InternalServerError(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -60,14 +68,14 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var req models.CreateCategoryRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
 		return
 	}
 
-	category, err := h.categoryService.CreateCategory(req)
+	category, err := h.categoryService.CreateCategory(middleware.CurrentUserID(c), req)
 	if err != nil {
-		utils.BadRequest(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -84,18 +92,14 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 	var req models.UpdateCategoryRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
 		return
 	}
 
-	category, err := h.categoryService.UpdateCategory(uint(id), req)
+	category, err := h.categoryService.UpdateCategory(middleware.CurrentUserID(c), uint(id), req)
 	if err != nil {
-		if err.Error() == "category not found" {
-			utils.NotFound(c, err.Error())
-			return
-		}
-		utils.BadRequest(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -110,13 +114,15 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	err = h.categoryService.DeleteCategory(uint(id))
+	var params models.DeleteCategoryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		httperr.BindingError(c, err, "Invalid query parameters")
+		return
+	}
+
+	err = h.categoryService.DeleteCategory(middleware.CurrentUserID(c), uint(id), params)
 	if err != nil {
-		if err.Error() == "category not found" {
-			utils.NotFound(c, err.Error())
-			return
-		}
-		utils.BadRequest(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -0,0 +1,443 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+const (
+	collabWriteWait      = 10 * time.Second
+	collabPongWait       = 60 * time.Second
+	collabMaxMessageSize = 64 * 1024
+	collabSendBuffer     = 16
+
+	// collabPingPeriod also refreshes presence (before realtime.PresenceTTL
+	// runs out) and workspace memberships
+	collabPingPeriod = 30 * time.Second
+)
+
+// Message types of the collaboration protocol
+const (
+	collabSubscribe = "subscribe"
+	collabPresence  = "presence"
+	collabCommand   = "command"
+	collabPing      = "ping"
+
+	collabEvent      = "event"
+	collabSubscribed = "subscribed"
+	collabResult     = "result"
+	collabError      = "error"
+	collabPong       = "pong"
+)
+
+// Todo commands accepted over the socket
+const (
+	commandCreate = "create"
+	commandUpdate = "update"
+	commandToggle = "toggle"
+	commandDelete = "delete"
+)
+
+// collabMessage is a message sent by the client
+type collabMessage struct {
+	Type        string          `json:"type"`
+	RequestID   string          `json:"request_id"`
+	CategoryIDs []uint          `json:"category_ids"`
+	TodoID      uint            `json:"todo_id"`
+	State       string          `json:"state"`
+	Action      string          `json:"action"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// collabReply is a message sent to the client
+type collabReply struct {
+	Type      string `json:"type"`
+	RequestID string `json:"request_id,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	Message   string `json:"message,omitempty"`
+	ErrorCode string `json:"error_code,omitempty"`
+	// Errors lists the invalid fields of the command data
+	Errors      []utils.FieldError `json:"errors,omitempty"`
+	CategoryIDs []uint             `json:"category_ids,omitempty"`
+	Data        interface{}        `json:"data,omitempty"`
+}
+
+// collabEventReply wraps a realtime event; presence events keep their own type
+type collabEventReply struct {
+	Type string `json:"type"`
+	realtime.Event
+}
+
+type CollabHandler struct {
+	upgrader         websocket.Upgrader
+	hub              *realtime.Hub
+	authService      repository.UserRepository
+	todoService      repository.TodoRepository
+	workspaceService repository.WorkspaceRepository
+	presenceService  repository.PresenceRepository
+}
+
+func NewCollabHandler(users repository.UserRepository, todos repository.TodoRepository, workspaces repository.WorkspaceRepository, presence repository.PresenceRepository, allowedOrigins []string) *CollabHandler {
+	return &CollabHandler{
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     middleware.WebSocketOrigin(allowedOrigins),
+		},
+		hub:              realtime.GetHub(),
+		authService:      users,
+		todoService:      todos,
+		workspaceService: workspaces,
+		presenceService:  presence,
+	}
+}
+
+// collabClient is one WebSocket connection. Only the write loop writes to the
+// socket; replies from the read loop go through send.
+type collabClient struct {
+	h      *CollabHandler
+	conn   *websocket.Conn
+	userID uint
+	// canWrite is false for API keys without the todos:write scope
+	canWrite bool
+
+	send chan collabReply
+	done chan struct{}
+
+	mu         sync.Mutex
+	workspaces map[uint]bool
+	categories map[uint]bool
+	presence   realtime.PresenceUpdate
+	todos      map[uint]realtime.PresenceUpdate
+}
+
+// Connect upgrades the request to a WebSocket collaboration channel
+func (h *CollabHandler) Connect(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+
+	user, err := h.authService.GetUserByID(userID)
+	if err != nil {
+		utils.Unauthorized(c, "user not found")
+		return
+	}
+
+	workspaces, err := memberWorkspaces(h.workspaceService, userID)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	canWrite := c.GetString(middleware.ContextAuthMethodKey) != middleware.AuthMethodAPIKey ||
+		models.HasScope(c.GetStringSlice(middleware.ContextScopesKey), "todos", true)
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written the error response
+		return
+	}
+
+	client := &collabClient{
+		h:          h,
+		conn:       conn,
+		userID:     userID,
+		canWrite:   canWrite,
+		send:       make(chan collabReply, collabSendBuffer),
+		done:       make(chan struct{}),
+		workspaces: workspaces,
+		categories: map[uint]bool{},
+		presence:   realtime.NewPresence(user.ID, user.Name),
+		todos:      map[uint]realtime.PresenceUpdate{},
+	}
+
+	sub, _, _ := h.hub.Subscribe(0)
+	go client.writeLoop(sub)
+
+	client.readLoop()
+
+	close(client.done)
+	sub.Close()
+	client.clearPresence()
+}
+
+func (cl *collabClient) readLoop() {
+	cl.conn.SetReadLimit(collabMaxMessageSize)
+	cl.conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	cl.conn.SetPongHandler(func(string) error {
+		return cl.conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	})
+
+	for {
+		_, payload, err := cl.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		cl.conn.SetReadDeadline(time.Now().Add(collabPongWait))
+
+		var msg collabMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			cl.reply(collabReply{Type: collabError, Status: http.StatusBadRequest, Message: "invalid message"})
+			continue
+		}
+		cl.handle(msg)
+	}
+}
+
+func (cl *collabClient) writeLoop(sub *realtime.Subscription) {
+	ticker := time.NewTicker(collabPingPeriod)
+	defer ticker.Stop()
+	defer cl.conn.Close()
+
+	for {
+		select {
+		case <-cl.done:
+			cl.write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// Too slow to keep up; the client reconnects and reloads
+				cl.write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"))
+				return
+			}
+			if !cl.wants(event) {
+				continue
+			}
+			replyType := collabEvent
+			if event.Type == realtime.PresenceEventType {
+				replyType = collabPresence
+			}
+			if err := cl.writeJSON(collabEventReply{Type: replyType, Event: event}); err != nil {
+				return
+			}
+		case reply := <-cl.send:
+			if err := cl.writeJSON(reply); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := cl.write(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			cl.refresh()
+		}
+	}
+}
+
+func (cl *collabClient) write(messageType int, data []byte) error {
+	cl.conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+	return cl.conn.WriteMessage(messageType, data)
+}
+
+func (cl *collabClient) writeJSON(v interface{}) error {
+	cl.conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+	return cl.conn.WriteJSON(v)
+}
+
+// reply queues a message for the write loop
+func (cl *collabClient) reply(reply collabReply) {
+	select {
+	case cl.send <- reply:
+	case <-cl.done:
+	}
+}
+
+// wants reports whether the event belongs to a workspace of the user and,
+// when the client subscribed to categories, to one of them
+func (cl *collabClient) wants(event realtime.Event) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if !cl.workspaces[event.WorkspaceID] {
+		return false
+	}
+	return len(cl.categories) == 0 || cl.categories[event.CategoryID]
+}
+
+// refresh keeps presence alive and picks up workspace membership changes
+func (cl *collabClient) refresh() {
+	if workspaces, err := memberWorkspaces(cl.h.workspaceService, cl.userID); err == nil {
+		cl.mu.Lock()
+		cl.workspaces = workspaces
+		cl.mu.Unlock()
+	}
+
+	cl.mu.Lock()
+	todos := make([]realtime.PresenceUpdate, 0, len(cl.todos))
+	for _, presence := range cl.todos {
+		todos = append(todos, presence)
+	}
+	cl.mu.Unlock()
+
+	for _, presence := range todos {
+		if err := cl.h.presenceService.RefreshPresence(&presence); err == nil {
+			cl.mu.Lock()
+			if _, ok := cl.todos[presence.TodoID]; ok {
+				cl.todos[presence.TodoID] = presence
+			}
+			cl.mu.Unlock()
+		}
+	}
+
+	cl.h.hub.SweepPresence(time.Now())
+}
+
+func (cl *collabClient) clearPresence() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	for todoID, presence := range cl.todos {
+		cl.h.presenceService.ClearPresence(presence)
+		delete(cl.todos, todoID)
+	}
+}
+
+func (cl *collabClient) handle(msg collabMessage) {
+	switch msg.Type {
+	case collabPing:
+		cl.reply(collabReply{Type: collabPong, RequestID: msg.RequestID})
+	case collabSubscribe:
+		cl.subscribe(msg)
+	case collabPresence:
+		cl.updatePresence(msg)
+	case collabCommand:
+		cl.command(msg)
+	default:
+		cl.reply(collabReply{Type: collabError, RequestID: msg.RequestID, Status: http.StatusBadRequest, Message: "unknown message type"})
+	}
+}
+
+// subscribe replaces the category filter; an empty list receives every category
+func (cl *collabClient) subscribe(msg collabMessage) {
+	categories := make(map[uint]bool, len(msg.CategoryIDs))
+	for _, id := range msg.CategoryIDs {
+		categories[id] = true
+	}
+
+	cl.mu.Lock()
+	cl.categories = categories
+	cl.mu.Unlock()
+
+	cl.reply(collabReply{Type: collabSubscribed, RequestID: msg.RequestID, CategoryIDs: msg.CategoryIDs})
+}
+
+func (cl *collabClient) updatePresence(msg collabMessage) {
+	if msg.State == realtime.PresenceIdle {
+		cl.mu.Lock()
+		presence, ok := cl.todos[msg.TodoID]
+		delete(cl.todos, msg.TodoID)
+		cl.mu.Unlock()
+
+		if ok {
+			cl.h.presenceService.ClearPresence(presence)
+		}
+		return
+	}
+
+	cl.mu.Lock()
+	base := cl.presence
+	cl.mu.Unlock()
+
+	presence, err := cl.h.presenceService.UpdatePresence(cl.userID, msg.TodoID, msg.State, base)
+	if err != nil {
+		cl.reply(errorReply(msg.RequestID, httperr.FromError(err)))
+		return
+	}
+
+	cl.mu.Lock()
+	cl.todos[msg.TodoID] = *presence
+	cl.mu.Unlock()
+}
+
+// command runs a todo mutation through TodoService, with the same validation
+// and error statuses as the REST endpoints. The resulting event is broadcast
+// to every client like any other change.
+func (cl *collabClient) command(msg collabMessage) {
+	fail := func(response utils.ErrorResponse) {
+		cl.reply(errorReply(msg.RequestID, response))
+	}
+
+	if !cl.canWrite {
+		fail(utils.NewErrorResponse(http.StatusForbidden, "api key is missing the required scope"))
+		return
+	}
+
+	todoService := cl.h.todoService
+	var (
+		todo    *models.Todo
+		err     error
+		status  = http.StatusOK
+		message string
+	)
+
+	switch msg.Action {
+	case commandCreate:
+		var req models.CreateTodoRequest
+		if err := decodeCommand(msg.Data, &req); err != nil {
+			fail(httperr.FromBindingError(err, "Invalid command data", msg.Data, nil))
+			return
+		}
+		todo, err = todoService.CreateTodo(cl.userID, req)
+		status, message = http.StatusCreated, "Todo created successfully"
+	case commandUpdate:
+		var req models.UpdateTodoRequest
+		if err := decodeCommand(msg.Data, &req); err != nil {
+			fail(httperr.FromBindingError(err, "Invalid command data", msg.Data, nil))
+			return
+		}
+		todo, err = todoService.UpdateTodo(cl.userID, msg.TodoID, req)
+		message = "Todo updated successfully"
+	case commandToggle:
+		todo, err = todoService.ToggleComplete(cl.userID, msg.TodoID)
+		message = "Todo completion status updated successfully"
+	case commandDelete:
+		err = todoService.DeleteTodo(cl.userID, msg.TodoID)
+		message = "Todo deleted successfully"
+	default:
+		fail(utils.NewErrorResponse(http.StatusBadRequest, "invalid action. Must be 'create', 'update', 'toggle', or 'delete'"))
+		return
+	}
+
+	if err != nil {
+		fail(httperr.FromError(err))
+		return
+	}
+
+	reply := collabReply{Type: collabResult, RequestID: msg.RequestID, Status: status, Message: message}
+	if todo != nil {
+		reply.Data = models.ToTodoResponse(*todo)
+	}
+	cl.reply(reply)
+}
+
+// decodeCommand decodes and validates command data like ShouldBindJSON
+func decodeCommand(data json.RawMessage, req interface{}) error {
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, req); err != nil {
+			return err
+		}
+	}
+	return binding.Validator.ValidateStruct(req)
+}
+
+// errorReply returns the reply of a failed request, with the status, message
+// and error code of the matching REST response
+func errorReply(requestID string, response utils.ErrorResponse) collabReply {
+	return collabReply{
+		Type:      collabError,
+		RequestID: requestID,
+		Status:    response.Code,
+		Message:   response.Message,
+		ErrorCode: response.ErrorCode,
+		Errors:    response.Errors,
+	}
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/jayasaleh/todo-list/be/internal/config"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+	"github.com/jayasaleh/todo-list/be/internal/repository/memory"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+)
+
+const testOrigin = "https://app.example.com"
+
+// collabTest serves the collaboration channel on the in-memory store
+type collabTest struct {
+	server *httptest.Server
+	store  *memory.Store
+	todos  *memory.TodoRepository
+	auth   *services.AuthService
+}
+
+func newCollabTest(t *testing.T) *collabTest {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+
+	store := memory.NewStore()
+	todos := memory.NewTodoRepository(store)
+	auth := services.NewAuthService(nil, &config.Config{JWTSecret: "test-secret", JWTIssuer: "todo-list-test", AccessTokenTTL: time.Hour})
+	h := NewCollabHandler(memory.NewUserRepository(store), todos, memory.NewWorkspaceRepository(store), memory.NewPresenceRepository(store), []string{testOrigin})
+
+	r := gin.New()
+	r.GET("/api/ws", middleware.QueryToken(), middleware.AuthMiddleware(auth, nil), middleware.RequireScope("todos"), h.Connect)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return &collabTest{server: server, store: store, todos: todos, auth: auth}
+}
+
+// dial connects as the user with the given Origin header; an empty origin
+// sends none
+func (ct *collabTest) dial(t *testing.T, userID uint, origin string) (*collabConn, *http.Response, error) {
+	t.Helper()
+
+	token, _, err := ct.auth.NewAccessToken(userID)
+	if err != nil {
+		t.Fatalf("NewAccessToken() error = %v", err)
+	}
+
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	url := "ws" + strings.TrimPrefix(ct.server.URL, "http") + "/api/ws?access_token=" + token
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		return nil, resp, err
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &collabConn{conn: conn}, resp, nil
+}
+
+func (ct *collabTest) connect(t *testing.T, userID uint) *collabConn {
+	t.Helper()
+
+	conn, _, err := ct.dial(t, userID, testOrigin)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	// The first reply means the connection is registered with the hub
+	conn.ping(t)
+	return conn
+}
+
+// collabConn is the client side of a connection
+type collabConn struct {
+	conn *websocket.Conn
+}
+
+// testReply is any message sent by the server
+type testReply struct {
+	Type        string          `json:"type"`
+	RequestID   string          `json:"request_id"`
+	Status      int             `json:"status"`
+	ErrorCode   string          `json:"error_code"`
+	CategoryIDs []uint          `json:"category_ids"`
+	ID          int64           `json:"id"`
+	Event       string          `json:"event"`
+	WorkspaceID uint            `json:"workspace_id"`
+	CategoryID  uint            `json:"category_id"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func (cc *collabConn) send(t *testing.T, msg collabMessage) {
+	t.Helper()
+
+	if err := cc.conn.WriteJSON(msg); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+}
+
+// next returns the next message of the given type, skipping the others
+func (cc *collabConn) next(t *testing.T, replyType string) testReply {
+	t.Helper()
+
+	for {
+		reply := cc.read(t)
+		if reply.Type == replyType {
+			return reply
+		}
+	}
+}
+
+func (cc *collabConn) read(t *testing.T) testReply {
+	t.Helper()
+
+	cc.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var reply testReply
+	if err := cc.conn.ReadJSON(&reply); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return reply
+}
+
+func (cc *collabConn) ping(t *testing.T) {
+	t.Helper()
+
+	cc.send(t, collabMessage{Type: collabPing, RequestID: "ping"})
+	cc.next(t, collabPong)
+}
+
+// request sends a message and returns the reply to it
+func (cc *collabConn) request(t *testing.T, msg collabMessage) testReply {
+	t.Helper()
+
+	msg.RequestID = strconv.FormatInt(time.Now().UnixNano(), 10)
+	cc.send(t, msg)
+	for {
+		reply := cc.read(t)
+		if reply.RequestID == msg.RequestID {
+			return reply
+		}
+	}
+}
+
+// eventsUntil returns the events received up to and including the one with
+// the given ID. Events arrive in the order of the hub, so anything filtered
+// out before it would have arrived first.
+func (cc *collabConn) eventsUntil(t *testing.T, id int64) []testReply {
+	t.Helper()
+
+	var events []testReply
+	for {
+		reply := cc.read(t)
+		if reply.Type != collabEvent {
+			continue
+		}
+		events = append(events, reply)
+		if reply.ID == id {
+			return events
+		}
+	}
+}
+
+// presence returns the users of the next presence update of the todo
+func (cc *collabConn) presence(t *testing.T, todoID uint) []realtime.PresenceUser {
+	t.Helper()
+
+	for {
+		reply := cc.next(t, collabPresence)
+		var data realtime.PresenceData
+		if err := json.Unmarshal(reply.Data, &data); err != nil {
+			t.Fatalf("invalid presence data %s: %v", reply.Data, err)
+		}
+		if data.TodoID == todoID {
+			return data.Users
+		}
+	}
+}
+
+var testEventID atomic.Int64
+
+func init() {
+	testEventID.Store(time.Now().UnixNano())
+}
+
+// broadcast sends a todo event to every connection and returns its ID
+func broadcast(workspaceID, categoryID uint) int64 {
+	id := testEventID.Add(1)
+	realtime.GetHub().Broadcast(realtime.Event{
+		ID:          id,
+		Type:        "todo.updated",
+		WorkspaceID: workspaceID,
+		CategoryID:  categoryID,
+		Data:        json.RawMessage(`{}`),
+	})
+	return id
+}
+
+func eventIDs(events []testReply) []int64 {
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func TestCollabConnect(t *testing.T) {
+	ct := newCollabTest(t)
+	ct.store.AddUser(1, "Owner")
+	ct.store.AddUser(2, "Viewer")
+	ct.store.AddUser(3, "Stranger")
+	workspaceID := ct.store.AddWorkspace(1)
+	ct.store.AddMember(workspaceID, 2, models.WorkspaceRoleViewer)
+	otherWorkspaceID := ct.store.AddWorkspace(3)
+
+	categories := memory.NewCategoryRepository(ct.store)
+	newCategory := func(userID uint, name string) *models.Category {
+		category, err := categories.CreateCategory(userID, models.CreateCategoryRequest{Name: name})
+		if err != nil {
+			t.Fatalf("CreateCategory() error = %v", err)
+		}
+		return category
+	}
+	home := newCategory(1, "Home")
+	work := newCategory(1, "Work")
+	other := newCategory(3, "Home")
+
+	newTodo := func(title string) *models.Todo {
+		todo, err := ct.todos.CreateTodo(1, models.CreateTodoRequest{Title: title, CategoryID: home.ID, Priority: models.PriorityLow})
+		if err != nil {
+			t.Fatalf("CreateTodo() error = %v", err)
+		}
+		return todo
+	}
+
+	t.Run("origin", func(t *testing.T) {
+		_, resp, err := ct.dial(t, 1, "https://evil.example.com")
+		if !errors.Is(err, websocket.ErrBadHandshake) || resp == nil || resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Dial() from another origin = %v, %v, want 403", resp, err)
+		}
+
+		for _, origin := range []string{testOrigin, ""} {
+			if _, _, err := ct.dial(t, 1, origin); err != nil {
+				t.Errorf("Dial() from %q error = %v", origin, err)
+			}
+		}
+
+		_, resp, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ct.server.URL, "http")+"/api/ws", nil)
+		if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Dial() without a token = %v, %v, want 401", resp, err)
+		}
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		conn := ct.connect(t, 1)
+
+		reply := conn.request(t, collabMessage{Type: collabSubscribe, CategoryIDs: []uint{home.ID}})
+		if reply.Type != collabSubscribed || len(reply.CategoryIDs) != 1 || reply.CategoryIDs[0] != home.ID {
+			t.Fatalf("subscribe reply = %+v", reply)
+		}
+
+		broadcast(workspaceID, work.ID)
+		broadcast(otherWorkspaceID, other.ID)
+		want := broadcast(workspaceID, home.ID)
+		if events := conn.eventsUntil(t, want); len(events) != 1 {
+			t.Errorf("events = %v, want only the home category", eventIDs(events))
+		}
+
+		// An empty list unsubscribes from the filter
+		conn.request(t, collabMessage{Type: collabSubscribe})
+		workEvent := broadcast(workspaceID, work.ID)
+		broadcast(otherWorkspaceID, other.ID)
+		homeEvent := broadcast(workspaceID, home.ID)
+		if got := eventIDs(conn.eventsUntil(t, homeEvent)); len(got) != 2 || got[0] != workEvent {
+			t.Errorf("events = %v, want %d and %d", got, workEvent, homeEvent)
+		}
+	})
+
+	t.Run("presence", func(t *testing.T) {
+		todo := newTodo("Fix the sink")
+		owner := ct.connect(t, 1)
+		viewer := ct.connect(t, 2)
+
+		viewer.send(t, collabMessage{Type: collabPresence, TodoID: todo.ID, State: realtime.PresenceViewing})
+		if users := owner.presence(t, todo.ID); len(users) != 1 || users[0] != (realtime.PresenceUser{UserID: 2, Name: "Viewer", State: realtime.PresenceViewing}) {
+			t.Fatalf("presence after join = %+v", users)
+		}
+
+		reply := viewer.request(t, collabMessage{Type: collabPresence, TodoID: todo.ID, State: realtime.PresenceEditing})
+		if reply.Type != collabError || reply.Status != http.StatusForbidden || reply.ErrorCode != "insufficient_permissions" {
+			t.Errorf("viewer editing = %+v, want 403", reply)
+		}
+
+		stranger := ct.connect(t, 3)
+		reply = stranger.request(t, collabMessage{Type: collabPresence, TodoID: todo.ID, State: realtime.PresenceViewing})
+		if reply.Type != collabError || reply.Status != http.StatusNotFound {
+			t.Errorf("non-member viewing = %+v, want 404", reply)
+		}
+
+		owner.send(t, collabMessage{Type: collabPresence, TodoID: todo.ID, State: realtime.PresenceEditing})
+		if users := owner.presence(t, todo.ID); len(users) != 2 || users[0].State != realtime.PresenceEditing || users[1].UserID != 2 {
+			t.Fatalf("presence after the owner joined = %+v", users)
+		}
+
+		// Disconnecting leaves every todo
+		viewer.conn.Close()
+		if users := owner.presence(t, todo.ID); len(users) != 1 || users[0].UserID != 1 {
+			t.Fatalf("presence after leave = %+v, want only the owner", users)
+		}
+
+		// Entries that are not refreshed expire
+		realtime.GetHub().SweepPresence(time.Now().Add(2 * realtime.PresenceTTL))
+		if users := owner.presence(t, todo.ID); len(users) != 0 {
+			t.Errorf("presence after expiry = %+v, want nobody", users)
+		}
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		todo := newTodo("Water the plants")
+		owner := ct.connect(t, 1)
+		viewer := ct.connect(t, 2)
+
+		for _, action := range []string{commandToggle, commandDelete} {
+			reply := viewer.request(t, collabMessage{Type: collabCommand, Action: action, TodoID: todo.ID})
+			if reply.Type != collabError || reply.Status != http.StatusForbidden || reply.ErrorCode != "insufficient_permissions" {
+				t.Errorf("viewer %s = %+v, want 403", action, reply)
+			}
+		}
+		if _, err := ct.todos.GetTodoByID(1, todo.ID); err != nil {
+			t.Fatalf("todo after the viewer's delete: %v", err)
+		}
+
+		reply := owner.request(t, collabMessage{Type: collabCommand, Action: commandToggle, TodoID: todo.ID})
+		var data models.TodoResponse
+		if err := json.Unmarshal(reply.Data, &data); err != nil {
+			t.Fatalf("invalid result data %s: %v", reply.Data, err)
+		}
+		if reply.Type != collabResult || reply.Status != http.StatusOK || !data.Completed {
+			t.Errorf("owner toggle = %+v, want the completed todo", reply)
+		}
+	})
+}
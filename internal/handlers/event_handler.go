@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+// eventHeartbeat keeps idle connections open through proxies. Workspace
+// memberships are refreshed at the same interval.
+const eventHeartbeat = 15 * time.Second
+
+// EventResetType tells a client that events were missed and it has to reload its data
+const EventResetType = "reset"
+
+type EventHandler struct {
+	hub              *realtime.Hub
+	workspaceService *services.WorkspaceService
+}
+
+func NewEventHandler(workspaceService *services.WorkspaceService) *EventHandler {
+	return &EventHandler{
+		hub:              realtime.GetHub(),
+		workspaceService: workspaceService,
+	}
+}
+
+// Stream Events (Server-Sent Events). Clients only receive events of the
+// workspaces they belong to and resume with the Last-Event-ID header.
+func (h *EventHandler) Stream(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+
+	workspaces, err := memberWorkspaces(h.workspaceService, userID)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		utils.BadRequest(c, "Invalid Last-Event-ID")
+		return
+	}
+
+	sub, replay, complete := h.hub.Subscribe(lastEventID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if lastEventID > 0 && !complete {
+		c.Render(-1, sse.Event{Event: EventResetType, Data: gin.H{"last_event_id": lastEventID}})
+	}
+	for _, event := range replay {
+		if workspaces[event.WorkspaceID] {
+			renderEvent(c, event)
+		}
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(eventHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// Dropped for being too slow; the client reconnects and replays
+				return
+			}
+			// Presence updates have no ID and are only sent over WebSocket
+			if event.ID != 0 && workspaces[event.WorkspaceID] {
+				renderEvent(c, event)
+				c.Writer.Flush()
+			}
+		case <-ticker.C:
+			if refreshed, err := memberWorkspaces(h.workspaceService, userID); err == nil {
+				workspaces = refreshed
+			}
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// memberWorkspaces returns the set of workspaces the user belongs to
+func memberWorkspaces(workspaceService repository.WorkspaceRepository, userID uint) (map[uint]bool, error) {
+	ids, err := workspaceService.GetMemberWorkspaceIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		workspaces[id] = true
+	}
+	return workspaces, nil
+}
+
+func renderEvent(c *gin.Context, event realtime.Event) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: event.Type,
+		Data:  event,
+	})
+}
+
+// parseLastEventID reads the Last-Event-ID header sent by EventSource on
+// reconnect, or the last_event_id query parameter for the first connection
+func parseLastEventID(c *gin.Context) (int64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
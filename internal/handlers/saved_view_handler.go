@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type SavedViewHandler struct {
+	savedViewService *services.SavedViewService
+}
+
+func NewSavedViewHandler(savedViewService *services.SavedViewService) *SavedViewHandler {
+	return &SavedViewHandler{
+		savedViewService: savedViewService,
+	}
+}
+
+// Get Views
+func (h *SavedViewHandler) GetViews(c *gin.Context) {
+	views, err := h.savedViewService.GetViews(middleware.CurrentUserID(c))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	viewResponses := []models.SavedViewResponse{}
+	for _, view := range views {
+		viewResponses = append(viewResponses, models.ToSavedViewResponse(view))
+	}
+
+	utils.OK(c, "Successfully fetching views", viewResponses)
+}
+
+// Get View by ID or built-in key
+func (h *SavedViewHandler) GetView(c *gin.Context) {
+	view, err := h.savedViewService.GetView(middleware.CurrentUserID(c), c.Param("id"))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Successfully fetching view", models.ToSavedViewResponse(*view))
+}
+
+// Create View
+func (h *SavedViewHandler) CreateView(c *gin.Context) {
+	var req models.CreateSavedViewRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	view, err := h.savedViewService.CreateView(middleware.CurrentUserID(c), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "View created successfully", models.ToSavedViewResponse(*view))
+}
+
+// Update View
+func (h *SavedViewHandler) UpdateView(c *gin.Context) {
+	var req models.UpdateSavedViewRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	view, err := h.savedViewService.UpdateView(middleware.CurrentUserID(c), c.Param("id"), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "View updated successfully", models.ToSavedViewResponse(*view))
+}
+
+// Delete View
+func (h *SavedViewHandler) DeleteView(c *gin.Context) {
+	if err := h.savedViewService.DeleteView(middleware.CurrentUserID(c), c.Param("id")); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "View deleted successfully", nil)
+}
+
+// Get View Todos runs the view. page, limit, cursor and include_total can be
+// passed to page through the results.
+func (h *SavedViewHandler) GetViewTodos(c *gin.Context) {
+	var params models.PaginationParams
+
+	if err := c.ShouldBindQuery(&params); err != nil {
+		httperr.BindingError(c, err, "Invalid query parameters")
+		return
+	}
+
+	todos, pagination, err := h.savedViewService.GetViewTodos(middleware.CurrentUserID(c), c.Param("id"), params)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	todoResponses := []models.TodoResponse{}
+	for _, todo := range todos {
+		todoResponses = append(todoResponses, models.ToTodoResponse(todo))
+	}
+
+	utils.PaginatedResponse(c, "Successfully fetched todos", todoResponses, pagination)
+}
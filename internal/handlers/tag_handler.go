@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type TagHandler struct {
+	tagService *services.TagService
+}
+
+func NewTagHandler(tagService *services.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// Get Tags
+func (h *TagHandler) GetTags(c *gin.Context) {
+	var workspaceID uint64
+	if value := c.Query("workspace_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequest(c, "Invalid workspace ID")
+			return
+		}
+		workspaceID = id
+	}
+
+	tags, err := h.tagService.GetAllTags(middleware.CurrentUserID(c), uint(workspaceID))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	tagResponses := []models.TagResponse{}
+	for _, tag := range tags {
+		tagResponses = append(tagResponses, models.ToTagResponse(tag))
+	}
+
+	utils.OK(c, "Successfully fetching tags", tagResponses)
+}
+
+// Get Tag by ID
+func (h *TagHandler) GetTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	tag, err := h.tagService.GetTagByID(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Successfully fetching tag", models.ToTagResponse(*tag))
+}
+
+// Create Tag
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req models.CreateTagRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(middleware.CurrentUserID(c), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "Tag created successfully", models.ToTagResponse(*tag))
+}
+
+// Update Tag
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	var req models.UpdateTagRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(middleware.CurrentUserID(c), uint(id), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Tag updated successfully", models.ToTagResponse(*tag))
+}
+
+// Delete Tag
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	err = h.tagService.DeleteTag(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Tag deleted successfully", nil)
+}
@@ -4,18 +4,21 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
 	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
 	"github.com/jayasaleh/todo-list/be/internal/services"
 	"github.com/jayasaleh/todo-list/be/pkg/utils"
 )
 
 type TodoHandler struct {
-	todoService *services.TodoService
+	todoService repository.TodoRepository
 }
 
-func NewTodoHandler() *TodoHandler {
+func NewTodoHandler(todos repository.TodoRepository) *TodoHandler {
 	return &TodoHandler{
-		todoService: services.NewTodoService(),
+		todoService: todos,
 	}
 }
 
@@ -24,13 +27,18 @@ func (h *TodoHandler) GetTodos(c *gin.Context) {
 	var params models.PaginationParams
 
 	if err := c.ShouldBindQuery(&params); err != nil {
-		utils.BadRequest(c, "Invalid query parameters")
+		httperr.BindingError(c, err, "Invalid query parameters")
 		return
 	}
 
-	todos, pagination, err := h.todoService.GetTodos(params)
+	if err := services.ValidateTodoFilters(params); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	todos, pagination, err := h.todoService.GetTodos(middleware.CurrentUserID(c), params)
 	if err != nil {
-		utils.InternalServerError(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -55,13 +63,9 @@ func (h *TodoHandler) GetTodo(c *gin.Context) {
 		return
 	}
 
-	todo, err := h.todoService.GetTodoByID(uint(id))
+	todo, err := h.todoService.GetTodoByID(middleware.CurrentUserID(c), uint(id))
 	if err != nil {
-		if err.Error() == "todo not found" {
-			utils.NotFound(c, err.Error())
-			return
-		}
-		utils.InternalServerError(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -72,14 +76,14 @@ func (h *TodoHandler) GetTodo(c *gin.Context) {
 func (h *TodoHandler) CreateTodo(c *gin.Context) {
 	var req models.CreateTodoRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
 		return
 	}
 
-	todo, err := h.todoService.CreateTodo(req)
+	todo, err := h.todoService.CreateTodo(middleware.CurrentUserID(c), req)
 	if err != nil {
-		utils.BadRequest(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -96,18 +100,14 @@ func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 
 	var req models.UpdateTodoRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
 		return
 	}
 
-	todo, err := h.todoService.UpdateTodo(uint(id), req)
+	todo, err := h.todoService.UpdateTodo(middleware.CurrentUserID(c), uint(id), req)
 	if err != nil {
-		if err.Error() == "todo not found" {
-			utils.NotFound(c, err.Error())
-			return
-		}
-		utils.BadRequest(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -122,13 +122,9 @@ func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 		return
 	}
 
-	err = h.todoService.DeleteTodo(uint(id))
+	err = h.todoService.DeleteTodo(middleware.CurrentUserID(c), uint(id))
 	if err != nil {
-		if err.Error() == "todo not found" {
-			utils.NotFound(c, err.Error())
-			return
-		}
-		utils.InternalServerError(c, err.Error())
+		httperr.Error(c, err)
 		return
 	}
 
@@ -143,15 +139,64 @@ func (h *TodoHandler) ToggleComplete(c *gin.Context) {
 		return
 	}
 
-	todo, err := h.todoService.ToggleComplete(uint(id))
+	todo, err := h.todoService.ToggleComplete(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Todo completion status updated successfully", models.ToTodoResponse(*todo))
+}
+
+// Bulk Update applies one action to many todos
+func (h *TodoHandler) BulkUpdate(c *gin.Context) {
+	var req models.BulkTodoRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	results, rolledBack, err := h.todoService.BulkUpdate(middleware.CurrentUserID(c), req)
 	if err != nil {
-		if err.Error() == "todo not found" {
-			utils.NotFound(c, err.Error())
+		httperr.Error(c, err)
+		return
+	}
+
+	response := models.ToBulkTodoResponse(req, results, rolledBack)
+	if rolledBack {
+		// The results tell the client which todo made the operation fail
+		errResponse := httperr.FromError(services.ErrBulkRolledBack)
+		errResponse.Data = response
+		c.JSON(errResponse.Code, errResponse)
+		return
+	}
+
+	utils.OK(c, "Bulk operation completed", response)
+}
+
+// Preview the next occurrences of a recurring todo
+func (h *TodoHandler) GetOccurrences(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid todo ID")
+		return
+	}
+
+	count := 5
+	if value := c.Query("count"); value != "" {
+		count, err = strconv.Atoi(value)
+		if err != nil || count < 1 || count > 50 {
+			utils.BadRequest(c, "count must be a number between 1 and 50")
 			return
 		}
-		utils.InternalServerError(c, err.Error())
+	}
+
+	occurrences, err := h.todoService.GetOccurrences(middleware.CurrentUserID(c), uint(id), count)
+	if err != nil {
+		httperr.Error(c, err)
 		return
 	}
 
-	utils.OK(c, "Todo completion status updated successfully", models.ToTodoResponse(*todo))
+	utils.OK(c, "Successfully fetching todo occurrences", occurrences)
 }
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type TodoItemHandler struct {
+	todoItemService *services.TodoItemService
+}
+
+func NewTodoItemHandler(todoItemService *services.TodoItemService) *TodoItemHandler {
+	return &TodoItemHandler{
+		todoItemService: todoItemService,
+	}
+}
+
+// parseTodoItemIDs reads the todo and item IDs from the path
+func parseTodoItemIDs(c *gin.Context, withItem bool) (uint, uint, bool) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid todo ID")
+		return 0, 0, false
+	}
+
+	if !withItem {
+		return uint(todoID), 0, true
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid todo item ID")
+		return 0, 0, false
+	}
+
+	return uint(todoID), uint(itemID), true
+}
+
+// Get Todo Items
+func (h *TodoItemHandler) GetItems(c *gin.Context) {
+	todoID, _, ok := parseTodoItemIDs(c, false)
+	if !ok {
+		return
+	}
+
+	items, err := h.todoItemService.GetItems(middleware.CurrentUserID(c), todoID)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	itemResponses := []models.TodoItemResponse{}
+	for _, item := range items {
+		itemResponses = append(itemResponses, models.ToTodoItemResponse(item))
+	}
+
+	utils.OK(c, "Successfully fetching todo items", itemResponses)
+}
+
+// Create Todo Item
+func (h *TodoItemHandler) CreateItem(c *gin.Context) {
+	todoID, _, ok := parseTodoItemIDs(c, false)
+	if !ok {
+		return
+	}
+
+	var req models.CreateTodoItemRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	item, err := h.todoItemService.CreateItem(middleware.CurrentUserID(c), todoID, req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "Todo item created successfully", models.ToTodoItemResponse(*item))
+}
+
+// Update Todo Item
+func (h *TodoItemHandler) UpdateItem(c *gin.Context) {
+	todoID, itemID, ok := parseTodoItemIDs(c, true)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTodoItemRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	item, err := h.todoItemService.UpdateItem(middleware.CurrentUserID(c), todoID, itemID, req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Todo item updated successfully", models.ToTodoItemResponse(*item))
+}
+
+// Delete Todo Item
+func (h *TodoItemHandler) DeleteItem(c *gin.Context) {
+	todoID, itemID, ok := parseTodoItemIDs(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.todoItemService.DeleteItem(middleware.CurrentUserID(c), todoID, itemID); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Todo item deleted successfully", nil)
+}
+
+// Toggle Todo Item Complete
+func (h *TodoItemHandler) ToggleItem(c *gin.Context) {
+	todoID, itemID, ok := parseTodoItemIDs(c, true)
+	if !ok {
+		return
+	}
+
+	item, err := h.todoItemService.ToggleItem(middleware.CurrentUserID(c), todoID, itemID)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Todo item completion status updated successfully", models.ToTodoItemResponse(*item))
+}
+
+// Reorder Todo Items
+func (h *TodoItemHandler) ReorderItems(c *gin.Context) {
+	todoID, _, ok := parseTodoItemIDs(c, false)
+	if !ok {
+		return
+	}
+
+	var req models.ReorderTodoItemsRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	items, err := h.todoItemService.ReorderItems(middleware.CurrentUserID(c), todoID, req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	itemResponses := []models.TodoItemResponse{}
+	for _, item := range items {
+		itemResponses = append(itemResponses, models.ToTodoItemResponse(item))
+	}
+
+	utils.OK(c, "Todo items reordered successfully", itemResponses)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// Get Trash
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	var params models.TrashParams
+
+	if err := c.ShouldBindQuery(&params); err != nil {
+		httperr.BindingError(c, err, "Invalid query parameters")
+		return
+	}
+
+	items, pagination, err := h.trashService.GetTrash(middleware.CurrentUserID(c), params)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	if items == nil {
+		items = []models.TrashItem{}
+	}
+
+	utils.PaginatedResponse(c, "Successfully fetching trash", items, pagination)
+}
+
+// Restore Todo
+func (h *TrashHandler) RestoreTodo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid todo ID")
+		return
+	}
+
+	var params models.RestoreTodoParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		httperr.BindingError(c, err, "Invalid query parameters")
+		return
+	}
+
+	todo, err := h.trashService.RestoreTodo(middleware.CurrentUserID(c), uint(id), params.RestoreCategory)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Todo restored successfully", models.ToTodoResponse(*todo))
+}
+
+// Restore Category
+func (h *TrashHandler) RestoreCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	category, err := h.trashService.RestoreCategory(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Category restored successfully", models.ToCategoryResponse(*category))
+}
+
+// Purge Todo
+func (h *TrashHandler) PurgeTodo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid todo ID")
+		return
+	}
+
+	if err := h.trashService.PurgeTodo(middleware.CurrentUserID(c), uint(id)); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Todo permanently deleted", nil)
+}
+
+// Purge Category
+func (h *TrashHandler) PurgeCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	if err := h.trashService.PurgeCategory(middleware.CurrentUserID(c), uint(id)); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Category permanently deleted", nil)
+}
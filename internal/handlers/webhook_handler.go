@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// Get Webhooks
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	var workspaceID uint64
+	if value := c.Query("workspace_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequest(c, "Invalid workspace ID")
+			return
+		}
+		workspaceID = id
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(middleware.CurrentUserID(c), uint(workspaceID))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	webhookResponses := []models.WebhookResponse{}
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, models.ToWebhookResponse(webhook))
+	}
+
+	utils.OK(c, "Successfully fetching webhooks", webhookResponses)
+}
+
+// Get Webhook by ID
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhookByID(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Successfully fetching webhook", models.ToWebhookResponse(*webhook))
+}
+
+// Create Webhook
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.CreateWebhookRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(middleware.CurrentUserID(c), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "Webhook created successfully. Store the secret now, it will not be shown again", models.CreatedWebhookResponse{
+		WebhookResponse: models.ToWebhookResponse(*webhook),
+		Secret:          webhook.Secret,
+	})
+}
+
+// Update Webhook
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(middleware.CurrentUserID(c), uint(id), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Webhook updated successfully", models.ToWebhookResponse(*webhook))
+}
+
+// Delete Webhook
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(middleware.CurrentUserID(c), uint(id)); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Webhook deleted successfully", nil)
+}
+
+// Get Webhook Deliveries
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	var params models.PaginationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		httperr.BindingError(c, err, "Invalid query parameters")
+		return
+	}
+
+	deliveries, pagination, err := h.webhookService.GetDeliveries(middleware.CurrentUserID(c), uint(id), params)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	deliveryResponses := []models.WebhookDeliveryResponse{}
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, models.ToWebhookDeliveryResponse(delivery))
+	}
+
+	utils.PaginatedResponse(c, "Successfully fetching webhook deliveries", deliveryResponses, pagination)
+}
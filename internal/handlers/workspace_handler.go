@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
+	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+type WorkspaceHandler struct {
+	workspaceService *services.WorkspaceService
+}
+
+func NewWorkspaceHandler(workspaceService *services.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService: workspaceService,
+	}
+}
+
+// Get Workspaces
+func (h *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+	workspaces, roles, err := h.workspaceService.GetWorkspaces(middleware.CurrentUserID(c))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	workspaceResponses := []models.WorkspaceResponse{}
+	for _, workspace := range workspaces {
+		workspaceResponses = append(workspaceResponses, models.ToWorkspaceResponse(workspace, roles[workspace.ID]))
+	}
+
+	utils.OK(c, "Successfully fetching workspaces", workspaceResponses)
+}
+
+// Get Workspace by ID
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	workspace, role, err := h.workspaceService.GetWorkspaceByID(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Successfully fetching workspace", models.ToWorkspaceResponse(*workspace, role))
+}
+
+// Create Workspace
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	var req models.CreateWorkspaceRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(middleware.CurrentUserID(c), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "Workspace created successfully", models.ToWorkspaceResponse(*workspace, models.WorkspaceRoleOwner))
+}
+
+// Update Workspace
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	var req models.UpdateWorkspaceRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	workspace, role, err := h.workspaceService.UpdateWorkspace(middleware.CurrentUserID(c), uint(id), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Workspace updated successfully", models.ToWorkspaceResponse(*workspace, role))
+}
+
+// Delete Workspace
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	if err := h.workspaceService.DeleteWorkspace(middleware.CurrentUserID(c), uint(id)); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Workspace deleted successfully", nil)
+}
+
+// Get Workspace Members
+func (h *WorkspaceHandler) GetMembers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	members, err := h.workspaceService.GetMembers(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	memberResponses := []models.WorkspaceMemberResponse{}
+	for _, member := range members {
+		memberResponses = append(memberResponses, models.ToWorkspaceMemberResponse(member))
+	}
+
+	utils.OK(c, "Successfully fetching workspace members", memberResponses)
+}
+
+// Update Workspace Member
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateWorkspaceMemberRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	member, err := h.workspaceService.UpdateMember(middleware.CurrentUserID(c), uint(id), uint(userID), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Workspace member updated successfully", models.ToWorkspaceMemberResponse(*member))
+}
+
+// Remove Workspace Member
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(middleware.CurrentUserID(c), uint(id), uint(userID)); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Workspace member removed successfully", nil)
+}
+
+// Get Workspace Invitations
+func (h *WorkspaceHandler) GetWorkspaceInvitations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	invitations, err := h.workspaceService.GetWorkspaceInvitations(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	invitationResponses := []models.InvitationResponse{}
+	for _, invitation := range invitations {
+		invitationResponses = append(invitationResponses, models.ToInvitationResponse(invitation))
+	}
+
+	utils.OK(c, "Successfully fetching invitations", invitationResponses)
+}
+
+// Create Invitation
+func (h *WorkspaceHandler) CreateInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	var req models.CreateInvitationRequest
+
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		httperr.BindingError(c, err, "Invalid request body")
+		return
+	}
+
+	invitation, err := h.workspaceService.CreateInvitation(middleware.CurrentUserID(c), uint(id), req)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.Created(c, "Invitation created successfully", models.ToInvitationResponse(*invitation))
+}
+
+// Cancel Invitation
+func (h *WorkspaceHandler) CancelInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid workspace ID")
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("invitationId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.workspaceService.CancelInvitation(middleware.CurrentUserID(c), uint(id), uint(invitationID)); err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	utils.OK(c, "Invitation cancelled successfully", nil)
+}
+
+// Get My Invitations
+func (h *WorkspaceHandler) GetMyInvitations(c *gin.Context) {
+	invitations, err := h.workspaceService.GetMyInvitations(middleware.CurrentUserID(c))
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	invitationResponses := []models.InvitationResponse{}
+	for _, invitation := range invitations {
+		invitationResponses = append(invitationResponses, models.ToInvitationResponse(invitation))
+	}
+
+	utils.OK(c, "Successfully fetching invitations", invitationResponses)
+}
+
+// Accept Invitation
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	h.respondInvitation(c, true)
+}
+
+// Decline Invitation
+func (h *WorkspaceHandler) DeclineInvitation(c *gin.Context) {
+	h.respondInvitation(c, false)
+}
+
+func (h *WorkspaceHandler) respondInvitation(c *gin.Context, accept bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid invitation ID")
+		return
+	}
+
+	invitation, err := h.workspaceService.RespondInvitation(middleware.CurrentUserID(c), uint(id), accept)
+	if err != nil {
+		httperr.Error(c, err)
+		return
+	}
+
+	message := "Invitation declined successfully"
+	if accept {
+		message = "Invitation accepted successfully"
+	}
+
+	utils.OK(c, message, models.ToInvitationResponse(*invitation))
+}
@@ -0,0 +1,292 @@
+// Package httperr turns the errors of the services and of request binding
+// into HTTP error responses.
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+// Error writes the response of an error returned by a service
+func Error(c *gin.Context, err error) {
+	response := FromError(err)
+	c.JSON(response.Code, response)
+}
+
+// BindingError writes the response of an error of ShouldBindBodyWithJSON or
+// ShouldBindQuery. message describes errors that concern no single field.
+func BindingError(c *gin.Context, err error, message string) {
+	var body []byte
+	if value, ok := c.Get(gin.BodyBytesKey); ok {
+		body, _ = value.([]byte)
+	}
+
+	response := FromBindingError(err, message, body, c.Request.URL.Query())
+	c.JSON(response.Code, response)
+}
+
+// FromError returns the response of an error returned by a service. Domain
+// errors (services.Error) get the status of their kind, their code and
+// message, and the invalid fields of validation errors. Other errors are
+// logged and answered with a generic 500, so that database messages do not
+// reach clients.
+func FromError(err error) utils.ErrorResponse {
+	var domainErr *services.Error
+	if !errors.As(err, &domainErr) {
+		log.Printf("Internal error: %v", err)
+		return utils.NewErrorResponse(http.StatusInternalServerError, "Internal server error")
+	}
+
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(domainErr.Kind, services.ErrValidation):
+		code = http.StatusBadRequest
+	case errors.Is(domainErr.Kind, services.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(domainErr.Kind, services.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(domainErr.Kind, services.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(domainErr.Kind, services.ErrConflict):
+		code = http.StatusConflict
+	}
+
+	// The message of err includes the details added by wrapping, e.g. the
+	// position of an invalid query
+	response := utils.NewErrorResponse(code, err.Error())
+	response.ErrorCode = domainErr.Code
+	for _, field := range domainErr.Fields {
+		message := field.Message
+		if len(domainErr.Fields) == 1 && err.Error() != domainErr.Message {
+			message = err.Error()
+		}
+		response.Errors = append(response.Errors, utils.FieldError{Field: field.Field, Code: field.Code, Message: message})
+	}
+	return response
+}
+
+// FromBindingError returns the response of a binding error, listing every
+// invalid field with the name used in the request. Some errors do not name
+// the field, e.g. an invalid time; it is then found by its value in the JSON
+// body or the query parameters.
+func FromBindingError(err error, message string, body []byte, query url.Values) utils.ErrorResponse {
+	response := utils.NewErrorResponse(http.StatusBadRequest, message)
+
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	var numErr *strconv.NumError
+	switch {
+	case errors.As(err, &validationErrs):
+		response.Message = "Validation failed"
+		response.ErrorCode = "validation_failed"
+		for _, fieldErr := range validationErrs {
+			response.Errors = append(response.Errors, utils.FieldError{
+				Field:   fieldName(fieldErr),
+				Code:    fieldErr.Tag(),
+				Message: fieldMessage(fieldErr),
+			})
+		}
+	case errors.As(err, &typeErr):
+		response.Message = "Validation failed"
+		response.ErrorCode = "validation_failed"
+		response.Errors = []utils.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "invalid_type",
+			Message: fmt.Sprintf("%s must be %s", typeErr.Field, typeName(typeErr.Type)),
+		}}
+	case errors.As(err, &timeErr):
+		response.Message = "Invalid time. Must be an RFC 3339 timestamp, e.g. 2024-01-31T09:00:00Z"
+		response.ErrorCode = "invalid_time"
+		if field := findField(timeErr.Value, body, query); field != "" {
+			response.Errors = []utils.FieldError{{
+				Field:   field,
+				Code:    "invalid_time",
+				Message: field + " must be an RFC 3339 timestamp",
+			}}
+		}
+	case errors.As(err, &numErr):
+		// Query parameters are parsed with strconv; JSON numbers of the
+		// wrong type are UnmarshalTypeErrors
+		response.Message = "Validation failed"
+		response.ErrorCode = "validation_failed"
+		if field := findField(numErr.Num, nil, query); field != "" {
+			response.Errors = []utils.FieldError{{
+				Field:   field,
+				Code:    "invalid_type",
+				Message: fmt.Sprintf("%s must be %s", field, parseFuncTypeName(numErr.Func)),
+			}}
+		}
+	}
+	return response
+}
+
+// UseFieldTagNames makes the validator report fields by their json (or form)
+// name instead of the Go field name. It must be called before the first
+// request is bound.
+func UseFieldTagNames() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// fieldName returns the path of the field without the name of the request
+// struct, e.g. "title" or "recurrence.frequency"
+func fieldName(fieldErr validator.FieldError) string {
+	_, name, ok := strings.Cut(fieldErr.Namespace(), ".")
+	if !ok {
+		return fieldErr.Field()
+	}
+	return name
+}
+
+// fieldMessage describes a failed validation rule
+func fieldMessage(fieldErr validator.FieldError) string {
+	field := fieldErr.Field()
+	switch fieldErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "min", "max":
+		bound := "at least"
+		if fieldErr.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters", field, bound, fieldErr.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must contain %s %s items", field, bound, fieldErr.Param())
+		}
+		return fmt.Sprintf("%s must be %s %s", field, bound, fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fieldErr.Param(), " ", ", "))
+	}
+	return fmt.Sprintf("%s is invalid (%s)", field, fieldErr.Tag())
+}
+
+// typeName describes a Go type in JSON terms
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return typeName(t.Elem())
+	}
+	return "an object"
+}
+
+// parseFuncTypeName describes the type expected by a strconv function
+func parseFuncTypeName(fn string) string {
+	switch fn {
+	case "ParseBool":
+		return "a boolean"
+	case "ParseInt", "Atoi":
+		return "an integer"
+	case "ParseUint":
+		return "a non-negative integer"
+	}
+	return "a number"
+}
+
+// findField returns the name of the JSON body field or query parameter that
+// holds the invalid value, or "" if there is none
+func findField(value string, body []byte, query url.Values) string {
+	if len(body) > 0 {
+		var decoded interface{}
+		if err := json.Unmarshal(body, &decoded); err == nil {
+			if field := findJSONField(decoded, "", value); field != "" {
+				return field
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, param := range query[key] {
+			// Multi-valued filters also accept comma separated values
+			for _, part := range strings.Split(param, ",") {
+				if part == value {
+					return key
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// findJSONField returns the path of the first string equal to value, e.g.
+// "due_date" or "filters.due_after"
+func findJSONField(node interface{}, path, value string) string {
+	switch node := node.(type) {
+	case string:
+		if node == value {
+			return path
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			if field := findJSONField(node[key], child, value); field != "" {
+				return field
+			}
+		}
+	case []interface{}:
+		for i, item := range node {
+			if field := findJSONField(item, fmt.Sprintf("%s[%d]", path, i), value); field != "" {
+				return field
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+package httperr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	UseFieldTagNames()
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      int
+		errorCode string
+		fields    []utils.FieldError
+	}{
+		{
+			name:      "not found",
+			err:       services.ErrTodoNotFound,
+			code:      http.StatusNotFound,
+			errorCode: "todo_not_found",
+		},
+		{
+			name:      "invalid field",
+			err:       services.ErrInvalidPriority,
+			code:      http.StatusBadRequest,
+			errorCode: "invalid_priority",
+			fields:    []utils.FieldError{{Field: "priority", Code: "invalid_priority", Message: services.ErrInvalidPriority.Message}},
+		},
+		{
+			// Wrapping adds details that belong to the field message
+			name:      "wrapped invalid field",
+			err:       fmt.Errorf("invalid query at position 3: %w", services.InvalidField("invalid_query", "q", "bad")),
+			code:      http.StatusBadRequest,
+			errorCode: "invalid_query",
+			fields:    []utils.FieldError{{Field: "q", Code: "invalid_query", Message: "invalid query at position 3: bad"}},
+		},
+		{
+			name:      "internal",
+			err:       errors.New("connection refused"),
+			code:      http.StatusInternalServerError,
+			errorCode: "internal_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromError(tt.err)
+			if got.Code != tt.code || got.ErrorCode != tt.errorCode || !reflect.DeepEqual(got.Errors, tt.fields) {
+				t.Errorf("FromError() = %d %q %+v, want %d %q %+v", got.Code, got.ErrorCode, got.Errors, tt.code, tt.errorCode, tt.fields)
+			}
+		})
+	}
+}
+
+func TestBindingError(t *testing.T) {
+	bindJSON := func(obj interface{}) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if err := c.ShouldBindBodyWithJSON(obj); err != nil {
+				BindingError(c, err, "Invalid request body")
+			}
+		}
+	}
+	bindQuery := func(c *gin.Context) {
+		var params models.PaginationParams
+		if err := c.ShouldBindQuery(&params); err != nil {
+			BindingError(c, err, "Invalid query parameters")
+		}
+	}
+
+	tests := []struct {
+		name      string
+		handler   gin.HandlerFunc
+		target    string
+		body      string
+		errorCode string
+		fields    []utils.FieldError
+	}{
+		{
+			name:      "required fields",
+			handler:   bindJSON(&models.CreateTodoRequest{}),
+			target:    "/",
+			body:      `{"priority": "low"}`,
+			errorCode: "validation_failed",
+			fields: []utils.FieldError{
+				{Field: "title", Code: "required", Message: "title is required"},
+				{Field: "category_id", Code: "required", Message: "category_id is required"},
+			},
+		},
+		{
+			name:      "json type mismatch",
+			handler:   bindJSON(&models.CreateTodoRequest{}),
+			target:    "/",
+			body:      `{"title": "x", "category_id": "abc", "priority": "low"}`,
+			errorCode: "validation_failed",
+			fields:    []utils.FieldError{{Field: "category_id", Code: "invalid_type", Message: "category_id must be a non-negative integer"}},
+		},
+		{
+			name:      "json time",
+			handler:   bindJSON(&models.CreateTodoRequest{}),
+			target:    "/",
+			body:      `{"title": "x", "category_id": 1, "priority": "low", "due_date": "tomorrow"}`,
+			errorCode: "invalid_time",
+			fields:    []utils.FieldError{{Field: "due_date", Code: "invalid_time", Message: "due_date must be an RFC 3339 timestamp"}},
+		},
+		{
+			name:      "nested json time",
+			handler:   bindJSON(&models.CreateSavedViewRequest{}),
+			target:    "/",
+			body:      `{"name": "x", "filters": {"due_after": "2024-13-01"}}`,
+			errorCode: "invalid_time",
+			fields:    []utils.FieldError{{Field: "filters.due_after", Code: "invalid_time", Message: "filters.due_after must be an RFC 3339 timestamp"}},
+		},
+		{
+			name:      "query number",
+			handler:   bindQuery,
+			target:    "/?category_id=1,abc",
+			errorCode: "validation_failed",
+			fields:    []utils.FieldError{{Field: "category_id", Code: "invalid_type", Message: "category_id must be a non-negative integer"}},
+		},
+		{
+			name:      "query boolean",
+			handler:   bindQuery,
+			target:    "/?completed=maybe",
+			errorCode: "validation_failed",
+			fields:    []utils.FieldError{{Field: "completed", Code: "invalid_type", Message: "completed must be a boolean"}},
+		},
+		{
+			name:      "query time",
+			handler:   bindQuery,
+			target:    "/?due_before=" + time.Now().Format("2006-01-02"),
+			errorCode: "invalid_time",
+			fields:    []utils.FieldError{{Field: "due_before", Code: "invalid_time", Message: "due_before must be an RFC 3339 timestamp"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/", tt.handler)
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			var got utils.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+			}
+			if rec.Code != http.StatusBadRequest || got.ErrorCode != tt.errorCode || !reflect.DeepEqual(got.Errors, tt.fields) {
+				t.Errorf("response = %d %q %+v, want 400 %q %+v", rec.Code, got.ErrorCode, got.Errors, tt.errorCode, tt.fields)
+			}
+		})
+	}
+}
@@ -1,13 +1,30 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSMiddleware - Enable CORS for the allowed origins. "*" allows any
+// origin; otherwise a listed Origin is echoed back.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	anyOrigin := containsOrigin(allowedOrigins, "*")
 
-// CORSMiddleware - Enable CORS
-func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		if anyOrigin {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			// The response depends on the Origin header, so caches must key on it
+			c.Writer.Header().Add("Vary", "Origin")
+			if origin := c.GetHeader("Origin"); origin != "" && containsOrigin(allowedOrigins, origin) {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Last-Event-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -18,3 +35,37 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// WebSocketOrigin returns the origin check for WebSocket upgrades. Browsers
+// do not apply CORS to WebSockets, so only clients without an Origin header
+// (not a browser), the same host and explicitly listed origins may connect.
+// "*" is not honoured here.
+func WebSocketOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		if strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		return containsOrigin(allowedOrigins, origin)
+	}
+}
+
+// containsOrigin reports whether origin is listed. Origins are compared
+// case-insensitively, ignoring a trailing slash.
+func containsOrigin(origins []string, origin string) bool {
+	origin = strings.TrimSuffix(origin, "/")
+	for _, allowed := range origins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		allowed    []string
+		origin     string
+		wantOrigin string
+		wantVary   bool
+	}{
+		{name: "any origin", allowed: []string{"*"}, origin: "https://app.example.com", wantOrigin: "*"},
+		{name: "listed origin", allowed: []string{"https://app.example.com"}, origin: "https://app.example.com", wantOrigin: "https://app.example.com", wantVary: true},
+		{name: "listed origin ignores case", allowed: []string{"https://App.example.com/"}, origin: "https://app.example.com", wantOrigin: "https://app.example.com", wantVary: true},
+		{name: "other origin", allowed: []string{"https://app.example.com"}, origin: "https://evil.example.com", wantVary: true},
+		{name: "no origin", allowed: []string{"https://app.example.com"}, wantVary: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(CORSMiddleware(tt.allowed))
+			router.GET("/api/todos", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/api/todos", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := w.Header().Get("Vary") == "Origin"; got != tt.wantVary {
+				t.Errorf("Vary: Origin = %v, want %v", got, tt.wantVary)
+			}
+		})
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{name: "no origin", allowed: nil, origin: "", want: true},
+		{name: "same host", allowed: nil, origin: "http://api.example.com", want: true},
+		{name: "listed origin", allowed: []string{"https://app.example.com"}, origin: "https://app.example.com", want: true},
+		{name: "other origin", allowed: []string{"https://app.example.com"}, origin: "https://evil.example.com", want: false},
+		{name: "wildcard is not honoured", allowed: []string{"*"}, origin: "https://evil.example.com", want: false},
+		{name: "same host on another port", allowed: nil, origin: "http://api.example.com:8080", want: false},
+		{name: "invalid origin", allowed: nil, origin: "://", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://api.example.com/api/ws", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if got := WebSocketOrigin(tt.allowed)(req); got != tt.want {
+				t.Errorf("WebSocketOrigin(%v)(%q) = %v, want %v", tt.allowed, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/api/todos", want: "/api/todos"},
+		{path: "/api/todos?page=2", want: "/api/todos?page=2"},
+		{path: "/api/events?access_token=eyJhbGciOi.x.y", want: "/api/events?access_token=REDACTED"},
+		{path: "/api/ws?a=1&access_token=tdl_secret&b=2", want: "/api/ws?a=1&access_token=REDACTED&b=2"},
+		{path: "/api/ws?access_token=one&access_token=two", want: "/api/ws?access_token=REDACTED&access_token=REDACTED"},
+		{path: "/api/ws?access%5Ftoken=tdl_secret", want: "/api/ws?access_token=REDACTED"},
+		{path: "/api/ws?access_token", want: "/api/ws?access_token=REDACTED"},
+		{path: "/api/ws?my_access_token=keep", want: "/api/ws?my_access_token=keep"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := redactPath(tt.path); got != tt.want {
+				t.Errorf("redactPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerRedactsQueryToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	writer := gin.DefaultWriter
+	gin.DefaultWriter = &buf
+	t.Cleanup(func() { gin.DefaultWriter = writer })
+
+	router := gin.New()
+	router.Use(Logger(), QueryToken())
+	router.GET("/api/events", func(c *gin.Context) {
+		if got := BearerToken(c); got != "tdl_secret" {
+			t.Errorf("BearerToken() = %q, want the query token", got)
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/events?access_token=tdl_secret", nil))
+
+	log := buf.String()
+	if strings.Contains(log, "tdl_secret") {
+		t.Errorf("log contains the token: %s", log)
+	}
+	if !strings.Contains(log, "/api/events?access_token=REDACTED") {
+		t.Errorf("log does not contain the redacted path: %s", log)
+	}
+}
@@ -0,0 +1,133 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is one numbered pair of SQL files, e.g.
+// up/015_create_saved_views.up.sql and down/015_create_saved_views.down.sql
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the SHA-256 of the up file, used to detect edited files
+	Checksum string
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in the up and down directories of fsys, ordered
+// by version. Every migration needs an up file; the down file is optional.
+func Load(fsys fs.FS) ([]Migration, error) {
+	byVersion := make(map[int]*Migration)
+
+	for _, direction := range []string{"up", "down"} {
+		entries, err := fs.ReadDir(fsys, direction)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s migrations: %w", direction, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+			match := fileName.FindStringSubmatch(entry.Name())
+			if match == nil || match[3] != direction {
+				return nil, fmt.Errorf("invalid migration file name %s/%s", direction, entry.Name())
+			}
+			version, _ := strconv.Atoi(match[1])
+			if version == 0 {
+				return nil, fmt.Errorf("invalid migration version in %s/%s", direction, entry.Name())
+			}
+
+			content, err := fs.ReadFile(fsys, path.Join(direction, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s/%s: %w", direction, entry.Name(), err)
+			}
+
+			migration, ok := byVersion[version]
+			if !ok {
+				if direction == "down" {
+					return nil, fmt.Errorf("down migration %s has no up migration", entry.Name())
+				}
+				migration = &Migration{Version: version, Name: match[2]}
+				byVersion[version] = migration
+			} else if migration.Name != match[2] || (direction == "up" && migration.Up != "") || (direction == "down" && migration.Down != "") {
+				return nil, fmt.Errorf("duplicate migration version %d", version)
+			}
+
+			if direction == "up" {
+				sum := sha256.Sum256(content)
+				migration.Up = string(content)
+				migration.Checksum = hex.EncodeToString(sum[:])
+			} else {
+				migration.Down = string(content)
+			}
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Create writes an empty up and down file for the next version to dir and
+// returns their paths
+func Create(dir, name string) (string, string, error) {
+	name = strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is required")
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	version := 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	title := strings.ToUpper(name[:1]) + strings.ReplaceAll(name[1:], "_", " ")
+	base := fmt.Sprintf("%03d_%s", version, name)
+	upPath := filepath.Join(dir, "up", base+".up.sql")
+	downPath := filepath.Join(dir, "down", base+".down.sql")
+
+	if err := writeNewFile(upPath, fmt.Sprintf("-- %s\n\n-- Step 1:\n", title)); err != nil {
+		return "", "", err
+	}
+	if err := writeNewFile(downPath, fmt.Sprintf("-- Rollback: %s\n\n-- Step 1:\n", title)); err != nil {
+		return "", "", err
+	}
+
+	return upPath, downPath, nil
+}
+
+func writeNewFile(name, content string) error {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return file.Close()
+}
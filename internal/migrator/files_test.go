@@ -0,0 +1,107 @@
+package migrator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := testFS(2)
+	fsys["up/003_no_down.up.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	fsys["up/README.md"] = &fstest.MapFile{Data: []byte("not a migration")}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("Load() = %d migrations, want 3", len(migrations))
+	}
+	for i, migration := range migrations {
+		if migration.Version != i+1 || migration.Up == "" || migration.Checksum == "" {
+			t.Errorf("migration %d = %+v", i, migration)
+		}
+	}
+	if migrations[1].Name != "create_t2" || migrations[1].Down != "DROP TABLE t2;" || migrations[2].Down != "" {
+		t.Errorf("migrations = %+v", migrations)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		message string
+	}{
+		{"upper case name", map[string]string{"up/001_Create.up.sql": ""}, "invalid migration file name up/001_Create.up.sql"},
+		{"no version", map[string]string{"up/create_t1.up.sql": ""}, "invalid migration file name"},
+		{"no direction", map[string]string{"up/001_create_t1.sql": ""}, "invalid migration file name"},
+		{"wrong directory", map[string]string{"up/001_create_t1.down.sql": ""}, "invalid migration file name up/001_create_t1.down.sql"},
+		{"version 0", map[string]string{"up/000_create_t1.up.sql": ""}, "invalid migration version"},
+		{"down without up", map[string]string{"down/001_create_t1.down.sql": ""}, "has no up migration"},
+		{"duplicate version", map[string]string{"up/001_create_t1.up.sql": "", "up/01_create_t2.up.sql": ""}, "duplicate migration version 1"},
+		{"names differ", map[string]string{"up/001_create_t1.up.sql": "", "down/001_create_t2.down.sql": ""}, "duplicate migration version 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"up":   &fstest.MapFile{Mode: os.ModeDir},
+				"down": &fstest.MapFile{Mode: os.ModeDir},
+			}
+			for name, content := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			if _, err := Load(fsys); err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Load() error = %v, want %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, direction := range []string{"up", "down"} {
+		if err := os.Mkdir(filepath.Join(dir, direction), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+
+	up, down, err := Create(dir, "Add Users!")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if want := filepath.Join(dir, "up", "001_add_users.up.sql"); up != want {
+		t.Errorf("Create() up = %s, want %s", up, want)
+	}
+	if want := filepath.Join(dir, "down", "001_add_users.down.sql"); down != want {
+		t.Errorf("Create() down = %s, want %s", down, want)
+	}
+	content, err := os.ReadFile(up)
+	if err != nil || !strings.HasPrefix(string(content), "-- Add users\n") {
+		t.Errorf("up file = %q, %v", content, err)
+	}
+	content, err = os.ReadFile(down)
+	if err != nil || !strings.HasPrefix(string(content), "-- Rollback: Add users\n") {
+		t.Errorf("down file = %q, %v", content, err)
+	}
+
+	// The next migration gets the next version
+	up, _, err = Create(dir, "add_index")
+	if err != nil || filepath.Base(up) != "002_add_index.up.sql" {
+		t.Errorf("Create() = %s, %v, want 002_add_index.up.sql", up, err)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil || len(migrations) != 2 {
+		t.Errorf("Load() of the created files = %d, %v", len(migrations), err)
+	}
+
+	if _, _, err := Create(dir, "!!!"); err == nil {
+		t.Error("Create() without a name succeeded")
+	}
+}
@@ -0,0 +1,388 @@
+// Package migrator applies the numbered SQL files in migrations/up and
+// migrations/down and records the applied versions in schema_migrations.
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockID is the Postgres advisory lock held while migrating, so that
+// concurrent deploys cannot apply the same migration twice
+const lockID int64 = 7240531208
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum VARCHAR(64) NOT NULL,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// ErrSchemaNewer is returned when the database has migrations applied that
+// are newer than the migration files, e.g. after a rollback of the binary
+var ErrSchemaNewer = errors.New("database schema is newer than the migrations")
+
+// SchemaMigration is a row of schema_migrations
+type SchemaMigration struct {
+	Version   int `gorm:"primaryKey"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// TableName specifies the table name for SchemaMigration model
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migration states reported by Status
+const (
+	StateApplied  = "applied"
+	StatePending  = "pending"
+	StateModified = "modified"
+	StateMissing  = "missing"
+)
+
+// MigrationStatus is one line of Status. Modified means the up file was
+// edited after it was applied; missing means the file of an applied
+// migration no longer exists.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New loads the migrations of fsys, which must contain the up and down
+// directories
+func New(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Latest returns the highest version of the migration files
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.Goto(ctx, m.Latest())
+}
+
+// Down rolls back the last n applied migrations
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	if n < 1 {
+		return 0, errors.New("number of migrations to roll back must be at least 1")
+	}
+
+	count := 0
+	err := m.withLock(ctx, func(applied map[int]SchemaMigration) error {
+		versions := appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && count < n; i-- {
+			if err := m.down(ctx, m.find(versions[i])); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Goto migrates up or down until version is the last applied migration;
+// version 0 rolls back everything
+func (m *Migrator) Goto(ctx context.Context, version int) (int, error) {
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("migration %d does not exist", version)
+	}
+
+	count := 0
+	err := m.withLock(ctx, func(applied map[int]SchemaMigration) error {
+		versions := appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && versions[i] > version; i-- {
+			if err := m.down(ctx, m.find(versions[i])); err != nil {
+				return err
+			}
+			count++
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.up(ctx, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for databases that were created before schema_migrations existed
+func (m *Migrator) Baseline(ctx context.Context, version int) (int, error) {
+	if m.find(version) == nil {
+		return 0, fmt.Errorf("migration %d does not exist", version)
+	}
+
+	count := 0
+	err := m.withLock(ctx, func(applied map[int]SchemaMigration) error {
+		if len(applied) > 0 {
+			return errors.New("baseline requires an empty schema_migrations table")
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if err := m.db.WithContext(ctx).Create(record(migration)).Error; err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Status lists every migration file and every applied migration
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, State: StatePending}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.State = StateApplied
+			if row.Checksum != migration.Checksum {
+				status.State = StateModified
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		if m.find(row.Version) == nil {
+			appliedAt := row.AppliedAt
+			statuses = append(statuses, MigrationStatus{Version: row.Version, Name: row.Name, State: StateMissing, AppliedAt: &appliedAt})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// Version returns the last applied migration, or 0 if none was applied
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	versions := appliedVersions(applied)
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[len(versions)-1], nil
+}
+
+// withLock runs fn while holding the migration lock, after checking that the
+// applied migrations still match the files
+func (m *Migrator) withLock(ctx context.Context, fn func(applied map[int]SchemaMigration) error) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.checkDrift(applied); err != nil {
+		return err
+	}
+
+	return fn(applied)
+}
+
+// lock takes the advisory lock on a dedicated connection. Other databases
+// have no advisory locks and are not locked.
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	if m.db.Dialector.Name() != "postgres" {
+		return func() {}, nil
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	log.Println("Waiting for migration lock")
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID); err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+		conn.Close()
+	}, nil
+}
+
+// applied creates schema_migrations if needed and returns its rows by version
+func (m *Migrator) applied(ctx context.Context) (map[int]SchemaMigration, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.Exec(createTable).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var rows []SchemaMigration
+	if err := db.Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	applied := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// checkDrift refuses to migrate when an applied migration was edited or
+// removed, or when an older migration was added after newer ones were applied
+func (m *Migrator) checkDrift(applied map[int]SchemaMigration) error {
+	versions := appliedVersions(applied)
+	if len(versions) > 0 && versions[len(versions)-1] > m.Latest() {
+		return fmt.Errorf("%w: database is at version %d, latest migration is %d", ErrSchemaNewer, versions[len(versions)-1], m.Latest())
+	}
+
+	last := 0
+	for version, row := range applied {
+		migration := m.find(version)
+		if migration == nil {
+			return fmt.Errorf("migration %d (%s) is applied but its file is missing", version, row.Name)
+		}
+		if migration.Checksum != row.Checksum {
+			return fmt.Errorf("migration %d (%s) was modified after it was applied", version, row.Name)
+		}
+		if version > last {
+			last = version
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version < last {
+			return fmt.Errorf("migration %d (%s) is pending but newer migrations are already applied", migration.Version, migration.Name)
+		}
+	}
+
+	return nil
+}
+
+// up applies a migration and records it in the same transaction
+func (m *Migrator) up(ctx context.Context, migration Migration) error {
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if strings.TrimSpace(migration.Up) != "" {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(record(migration)).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+
+	log.Printf("Applied migration %03d_%s", migration.Version, migration.Name)
+	return nil
+}
+
+// down rolls back a migration and removes its record in the same transaction
+func (m *Migrator) down(ctx context.Context, migration *Migration) error {
+	if strings.TrimSpace(migration.Down) == "" {
+		return fmt.Errorf("migration %d (%s) has no down migration", migration.Version, migration.Name)
+	}
+
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&SchemaMigration{}, migration.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+
+	log.Printf("Rolled back migration %03d_%s", migration.Version, migration.Name)
+	return nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func record(migration Migration) *SchemaMigration {
+	return &SchemaMigration{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Checksum:  migration.Checksum,
+		AppliedAt: time.Now().UTC(),
+	}
+}
+
+func appliedVersions(applied map[int]SchemaMigration) []int {
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
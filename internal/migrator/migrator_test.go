@@ -0,0 +1,200 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db
+}
+
+// testFS returns migrations 1..n; migration i creates table ti
+func testFS(n int) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for i := 1; i <= n; i++ {
+		table := fmt.Sprintf("t%d", i)
+		base := fmt.Sprintf("%03d_create_%s", i, table)
+		fsys["up/"+base+".up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE " + table + " (id INTEGER PRIMARY KEY);")}
+		fsys["down/"+base+".down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE " + table + ";")}
+	}
+	return fsys
+}
+
+func newTestMigrator(t *testing.T, db *gorm.DB, fsys fstest.MapFS) *Migrator {
+	t.Helper()
+
+	m, err := New(db, fsys)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return m
+}
+
+// checkVersion checks the last applied version and which tables exist
+func checkVersion(t *testing.T, db *gorm.DB, m *Migrator, want int) {
+	t.Helper()
+
+	version, err := m.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if version != want {
+		t.Errorf("Version() = %d, want %d", version, want)
+	}
+	for i := 1; i <= m.Latest(); i++ {
+		table := fmt.Sprintf("t%d", i)
+		if got := db.Migrator().HasTable(table); got != (i <= want) {
+			t.Errorf("table %s exists = %v at version %d", table, got, want)
+		}
+	}
+}
+
+func TestUpDownGoto(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newTestMigrator(t, db, testFS(3))
+
+	steps := []struct {
+		name    string
+		run     func() (int, error)
+		count   int
+		version int
+	}{
+		{"up", func() (int, error) { return m.Up(ctx) }, 3, 3},
+		{"up again", func() (int, error) { return m.Up(ctx) }, 0, 3},
+		{"down 1", func() (int, error) { return m.Down(ctx, 1) }, 1, 2},
+		{"goto down", func() (int, error) { return m.Goto(ctx, 1) }, 1, 1},
+		{"goto up", func() (int, error) { return m.Goto(ctx, 3) }, 2, 3},
+		{"down 2", func() (int, error) { return m.Down(ctx, 2) }, 2, 1},
+		{"goto 0", func() (int, error) { return m.Goto(ctx, 0) }, 1, 0},
+		{"down on an empty database", func() (int, error) { return m.Down(ctx, 1) }, 0, 0},
+	}
+
+	for _, step := range steps {
+		count, err := step.run()
+		if err != nil {
+			t.Fatalf("%s: error = %v", step.name, err)
+		}
+		if count != step.count {
+			t.Errorf("%s: count = %d, want %d", step.name, count, step.count)
+		}
+		checkVersion(t, db, m, step.version)
+	}
+
+	if _, err := m.Goto(ctx, 9); err == nil {
+		t.Error("Goto() to a missing version succeeded")
+	}
+	if _, err := m.Down(ctx, 0); err == nil {
+		t.Error("Down(0) succeeded")
+	}
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	fsys := testFS(2)
+	fsys["up/002_create_t2.up.sql"].Data = []byte("CREATE TABLE t2 (id INTEGER PRIMARY KEY); INSERT INTO missing VALUES (1);")
+	m := newTestMigrator(t, db, fsys)
+
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "failed to apply migration 2") {
+		t.Fatalf("Up() error = %v, want migration 2 to fail", err)
+	}
+	checkVersion(t, db, m, 1)
+}
+
+func TestChecksumDrift(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	fsys := testFS(2)
+	if _, err := newTestMigrator(t, db, fsys).Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	fsys["up/001_create_t1.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE t1 (id INTEGER PRIMARY KEY, name TEXT);")}
+	fsys["up/003_create_t3.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE t3 (id INTEGER PRIMARY KEY);")}
+	m := newTestMigrator(t, db, fsys)
+
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "migration 1 (create_t1) was modified after it was applied") {
+		t.Errorf("Up() error = %v, want a checksum error", err)
+	}
+	if db.Migrator().HasTable("t3") {
+		t.Error("Up() applied migration 3 despite the modified migration")
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	var states []string
+	for _, status := range statuses {
+		states = append(states, status.State)
+	}
+	if want := []string{StateModified, StateApplied, StatePending}; strings.Join(states, ",") != strings.Join(want, ",") {
+		t.Errorf("Status() states = %v, want %v", states, want)
+	}
+}
+
+func TestSchemaNewer(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	if _, err := newTestMigrator(t, db, testFS(3)).Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	// An older binary only knows the first two migrations
+	m := newTestMigrator(t, db, testFS(2))
+	if _, err := m.Up(ctx); !errors.Is(err, ErrSchemaNewer) {
+		t.Errorf("Up() error = %v, want ErrSchemaNewer", err)
+	}
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrSchemaNewer) {
+		t.Errorf("Down() error = %v, want ErrSchemaNewer", err)
+	}
+	checkVersion(t, db, newTestMigrator(t, db, testFS(3)), 3)
+}
+
+func TestBaseline(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newTestMigrator(t, db, testFS(3))
+
+	count, err := m.Baseline(ctx, 2)
+	if err != nil || count != 2 {
+		t.Fatalf("Baseline() = %d, %v, want 2", count, err)
+	}
+	version, err := m.Version(ctx)
+	if err != nil || version != 2 {
+		t.Errorf("Version() = %d, %v, want 2", version, err)
+	}
+	// Baselined migrations are recorded but not run
+	if db.Migrator().HasTable("t1") || db.Migrator().HasTable("t2") {
+		t.Error("Baseline() ran the migrations")
+	}
+
+	if count, err := m.Up(ctx); err != nil || count != 1 || !db.Migrator().HasTable("t3") {
+		t.Errorf("Up() after baseline = %d, %v, want migration 3 applied", count, err)
+	}
+	if _, err := m.Baseline(ctx, 1); err == nil {
+		t.Error("Baseline() of a migrated database succeeded")
+	}
+	if _, err := newTestMigrator(t, openTestDB(t), testFS(3)).Baseline(ctx, 9); err == nil {
+		t.Error("Baseline() to a missing version succeeded")
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// API key scopes. A write scope also grants read access to the same resource.
+const (
+	ScopeTodosRead       = "todos:read"
+	ScopeTodosWrite      = "todos:write"
+	ScopeCategoriesRead  = "categories:read"
+	ScopeCategoriesWrite = "categories:write"
+	ScopeTagsRead        = "tags:read"
+	ScopeTagsWrite       = "tags:write"
+)
+
+// Scopes lists every valid API key scope
+var Scopes = []string{
+	ScopeTodosRead, ScopeTodosWrite,
+	ScopeCategoriesRead, ScopeCategoriesWrite,
+	ScopeTagsRead, ScopeTagsWrite,
+}
+
+// APIKey is a personal, scoped key for scripts and integrations.
+// Only the SHA-256 hash of the key is stored; Prefix is kept for display.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"not null"`
+	KeyHash    string     `json:"-" gorm:"not null;uniqueIndex"`
+	Scopes     string     `json:"scopes" gorm:"not null"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relationship
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList returns the scopes of the key as a slice
+func (k APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// ValidateScope validates if the scope value is valid
+func ValidateScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// HasScope reports whether the granted scopes allow the requested one
+func HasScope(granted []string, resource string, write bool) bool {
+	for _, scope := range granted {
+		if scope == resource+":write" {
+			return true
+		}
+		if !write && scope == resource+":read" {
+			return true
+		}
+	}
+	return false
+}
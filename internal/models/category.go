@@ -7,15 +7,19 @@ import (
 )
 
 type Category struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"not null;uniqueIndex"`
-	Color     string         `json:"color" gorm:"default:'#3B82F6'"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint           `json:"workspace_id" gorm:"uniqueIndex:idx_categories_workspace_name"`
+	UserID      uint           `json:"user_id" gorm:"index"`
+	Name        string         `json:"name" gorm:"not null;uniqueIndex:idx_categories_workspace_name"`
+	Color       string         `json:"color" gorm:"default:'#3B82F6'"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relationship
-	Todos []Todo `json:"-" gorm:"foreignKey:CategoryID"`
+	Workspace *Workspace `json:"-" gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`
+	User      *User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+	Todos     []Todo     `json:"-" gorm:"foreignKey:CategoryID"`
 }
 
 // TableName specifies the table name for Category model
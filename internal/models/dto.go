@@ -1,49 +1,190 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Todo DTOs
 type CreateTodoRequest struct {
-	Title       string     `json:"title" binding:"required"`
-	Description string     `json:"description"`
-	CategoryID  uint       `json:"category_id" binding:"required"`
-	Priority    Priority   `json:"priority" binding:"required"`
-	DueDate     *time.Time `json:"due_date"`
+	Title        string          `json:"title" binding:"required"`
+	Description  string          `json:"description"`
+	CategoryID   uint            `json:"category_id" binding:"required"`
+	Priority     Priority        `json:"priority" binding:"required"`
+	DueDate      *time.Time      `json:"due_date"`
+	AutoComplete bool            `json:"auto_complete"`
+	TagIDs       []uint          `json:"tag_ids"`
+	Recurrence   *RecurrenceRule `json:"recurrence"`
+	// ReminderOffsets are minutes before the due date, e.g. [1440, 60]
+	ReminderOffsets []int `json:"reminder_offsets"`
 }
 
 type UpdateTodoRequest struct {
-	Title       *string    `json:"title"`
-	Description *string    `json:"description"`
-	CategoryID *uint      `json:"category_id" binding:"omitempty,required"`
-	Priority    *Priority  `json:"priority"`
-	Completed   *bool      `json:"completed"`
-	DueDate     *time.Time `json:"due_date"`
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	CategoryID   *uint      `json:"category_id" binding:"omitempty,required"`
+	Priority     *Priority  `json:"priority"`
+	Completed    *bool      `json:"completed"`
+	DueDate      *time.Time `json:"due_date"`
+	AutoComplete *bool      `json:"auto_complete"`
+	// TagIDs replaces the tags of the todo; an empty list removes all tags
+	TagIDs *[]uint `json:"tag_ids"`
+	// Recurrence replaces the rule and restarts the series at this todo;
+	// an empty frequency stops the recurrence
+	Recurrence *RecurrenceRule `json:"recurrence"`
+	// ReminderOffsets replaces the reminders; an empty list removes all reminders
+	ReminderOffsets *[]int `json:"reminder_offsets"`
+}
+
+// Bulk actions
+const (
+	BulkActionComplete    = "complete"
+	BulkActionUncomplete  = "uncomplete"
+	BulkActionDelete      = "delete"
+	BulkActionMove        = "move"
+	BulkActionSetPriority = "set_priority"
+	BulkActionSetDueDate  = "set_due_date"
+)
+
+// Bulk result statuses
+const (
+	BulkStatusSucceeded  = "succeeded"
+	BulkStatusUnchanged  = "unchanged"
+	BulkStatusFailed     = "failed"
+	BulkStatusRolledBack = "rolled_back"
+)
+
+// BulkTodoRequest applies one action to the todos listed in IDs or matched
+// by Filter
+type BulkTodoRequest struct {
+	IDs    []uint          `json:"ids"`
+	Filter *BulkTodoFilter `json:"filter"`
+	Action string          `json:"action" binding:"required"`
+	// Parameters of the move, set_priority and set_due_date actions. A null
+	// due_date removes the due date.
+	CategoryID *uint      `json:"category_id"`
+	Priority   *Priority  `json:"priority"`
+	DueDate    *time.Time `json:"due_date"`
+	// AllOrNothing rolls back every change when one todo fails
+	AllOrNothing bool `json:"all_or_nothing"`
+}
+
+// BulkTodoFilter selects todos like the search, q, workspace_id and filter
+// parameters of GET /api/todos
+type BulkTodoFilter struct {
+	WorkspaceID uint   `json:"workspace_id"`
+	Search      string `json:"search"`
+	Query       string `json:"q"`
+	TodoFilters
+}
+
+type BulkTodoResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkTodoResponse struct {
+	Action       string           `json:"action"`
+	AllOrNothing bool             `json:"all_or_nothing"`
+	RolledBack   bool             `json:"rolled_back"`
+	Total        int              `json:"total"`
+	Succeeded    int              `json:"succeeded"`
+	Unchanged    int              `json:"unchanged"`
+	Failed       int              `json:"failed"`
+	Results      []BulkTodoResult `json:"results"`
 }
 
 type TodoResponse struct {
-	ID          uint              `json:"id"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Completed   bool              `json:"completed"`
-	Category    *CategoryResponse `json:"category,omitempty"`
-	CategoryID  uint              `json:"category_id"`
-	Priority    Priority          `json:"priority"`
-	DueDate     *time.Time        `json:"due_date"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	ID           uint               `json:"id"`
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	Completed    bool               `json:"completed"`
+	WorkspaceID  uint               `json:"workspace_id"`
+	Category     *CategoryResponse  `json:"category,omitempty"`
+	CategoryID   uint               `json:"category_id"`
+	Tags         []TagResponse      `json:"tags"`
+	Priority     Priority           `json:"priority"`
+	DueDate      *time.Time         `json:"due_date"`
+	AutoComplete bool               `json:"auto_complete"`
+	Recurrence   *RecurrenceRule    `json:"recurrence"`
+	SeriesID     *uint              `json:"series_id,omitempty"`
+	Occurrence   int                `json:"occurrence,omitempty"`
+	Reminders    []ReminderResponse `json:"reminders"`
+	Items        []TodoItemResponse `json:"items"`
+	Progress     TodoProgress       `json:"progress"`
+	Search       *TodoSearchMatch   `json:"search,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
 }
 
-type CategoryResponse struct {
+// TodoSearchMatch is returned with todos found by a search. Title and Snippet
+// are HTML-escaped with the matched words wrapped in <mark> tags.
+type TodoSearchMatch struct {
+	Rank    float64 `json:"rank"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+}
+
+type TodoItemResponse struct {
 	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	Color     string    `json:"color"`
+	Title     string    `json:"title"`
+	Completed bool      `json:"completed"`
+	Position  int       `json:"position"`
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TodoProgress summarizes the checklist items of a todo, e.g. "3/5"
+type TodoProgress struct {
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+	Label     string `json:"label"`
+}
+
+type CategoryResponse struct {
+	ID          uint      `json:"id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TagResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	WorkspaceID uint      `json:"workspace_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ReminderResponse struct {
+	ID            uint       `json:"id"`
+	OffsetMinutes int        `json:"offset_minutes"`
+	RemindAt      time.Time  `json:"remind_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// Todo Item DTOs
+type CreateTodoItemRequest struct {
+	Title     string `json:"title" binding:"required"`
+	Completed bool   `json:"completed"`
+}
+
+type UpdateTodoItemRequest struct {
+	Title     *string `json:"title"`
+	Completed *bool   `json:"completed"`
+}
+
+type ReorderTodoItemsRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required"`
 }
 
 // Category DTOs
 type CreateCategoryRequest struct {
-	Name  string `json:"name" binding:"required"`
-	Color string `json:"color"`
+	Name        string `json:"name" binding:"required"`
+	Color       string `json:"color"`
+	WorkspaceID *uint  `json:"workspace_id"`
 }
 
 type UpdateCategoryRequest struct {
@@ -51,13 +192,288 @@ type UpdateCategoryRequest struct {
 	Color *string `json:"color"`
 }
 
+// Category delete strategies for the todos of a category
+const (
+	CategoryDeleteRestrict = "restrict"
+	CategoryDeleteReassign = "reassign"
+	CategoryDeleteCascade  = "cascade"
+)
+
+// DeleteCategoryParams are the query parameters of DELETE /api/categories/:id.
+// Target is the category that receives the todos with the reassign strategy.
+type DeleteCategoryParams struct {
+	Strategy string `form:"strategy"`
+	Target   uint   `form:"target"`
+}
+
+// Tag DTOs
+type CreateTagRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Color       string `json:"color"`
+	WorkspaceID *uint  `json:"workspace_id"`
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// Saved View DTOs
+// TodoFilters are the structured filters of GET /api/todos, as stored in
+// saved views and accepted by bulk operations. Field names match the query
+// parameters.
+type TodoFilters struct {
+	CategoryIDs   []uint     `json:"category_id"`
+	Priorities    []Priority `json:"priority"`
+	Tags          []string   `json:"tag"`
+	TagMode       string     `json:"tag_mode"`
+	Completed     *bool      `json:"completed"`
+	Overdue       bool       `json:"overdue"`
+	DueAfter      *time.Time `json:"due_after"`
+	DueBefore     *time.Time `json:"due_before"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+}
+
+// Empty reports whether no filter is set
+func (f TodoFilters) Empty() bool {
+	return len(f.CategoryIDs) == 0 && len(f.Priorities) == 0 && len(f.Tags) == 0 &&
+		f.Completed == nil && !f.Overdue &&
+		f.DueAfter == nil && f.DueBefore == nil && f.CreatedAfter == nil && f.CreatedBefore == nil
+}
+
+// Params returns todo list parameters with only these filters set
+func (f TodoFilters) Params() PaginationParams {
+	return PaginationParams{
+		CategoryIDs:   f.CategoryIDs,
+		Priorities:    f.Priorities,
+		Tags:          f.Tags,
+		TagMode:       f.TagMode,
+		Completed:     f.Completed,
+		Overdue:       f.Overdue,
+		DueAfter:      f.DueAfter,
+		DueBefore:     f.DueBefore,
+		CreatedAfter:  f.CreatedAfter,
+		CreatedBefore: f.CreatedBefore,
+	}
+}
+
+type CreateSavedViewRequest struct {
+	Name        string       `json:"name" binding:"required"`
+	WorkspaceID *uint        `json:"workspace_id"`
+	Search      string       `json:"search"`
+	Query       string       `json:"query"`
+	Sort        string       `json:"sort"`
+	Pagination  string       `json:"pagination"`
+	Limit       int          `json:"limit"`
+	Filters     *TodoFilters `json:"filters"`
+}
+
+type UpdateSavedViewRequest struct {
+	Name        *string `json:"name"`
+	WorkspaceID *uint   `json:"workspace_id"`
+	Search      *string `json:"search"`
+	Query       *string `json:"query"`
+	Sort        *string `json:"sort"`
+	Pagination  *string `json:"pagination"`
+	Limit       *int    `json:"limit"`
+	// Filters replaces all structured filters; {} removes them
+	Filters *TodoFilters `json:"filters"`
+}
+
+// SavedViewResponse describes a saved or built-in view. Built-in views have
+// a key instead of an ID.
+type SavedViewResponse struct {
+	ID          uint        `json:"id,omitempty"`
+	Key         string      `json:"key,omitempty"`
+	BuiltIn     bool        `json:"built_in"`
+	Name        string      `json:"name"`
+	WorkspaceID *uint       `json:"workspace_id"`
+	Search      string      `json:"search"`
+	Query       string      `json:"query"`
+	Sort        string      `json:"sort"`
+	Pagination  string      `json:"pagination"`
+	Limit       int         `json:"limit"`
+	Filters     TodoFilters `json:"filters"`
+	CreatedAt   *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`
+}
+
+// Auth DTOs
+type RegisterRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type UserResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type AuthResponse struct {
+	TokenType             string       `json:"token_type"`
+	AccessToken           string       `json:"access_token"`
+	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`
+	RefreshToken          string       `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
+	User                  UserResponse `json:"user"`
+}
+
+// API Key DTOs
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse includes the raw key, which is only shown once
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// Webhook DTOs
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret"`
+	WorkspaceID *uint    `json:"workspace_id"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url"`
+	Events *[]string `json:"events"`
+	Secret *string   `json:"secret"`
+	Active *bool     `json:"active"`
+}
+
+type WebhookResponse struct {
+	ID          uint      `json:"id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreatedWebhookResponse includes the signing secret, which is only shown once
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint       `json:"id"`
+	EventID        string     `json:"event_id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Workspace DTOs
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name *string `json:"name"`
+}
+
+type WorkspaceResponse struct {
+	ID        uint          `json:"id"`
+	Name      string        `json:"name"`
+	OwnerID   *uint         `json:"owner_id"`
+	Role      WorkspaceRole `json:"role,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type WorkspaceMemberResponse struct {
+	UserID    uint          `json:"user_id"`
+	Name      string        `json:"name"`
+	Email     string        `json:"email"`
+	Role      WorkspaceRole `json:"role"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type UpdateWorkspaceMemberRequest struct {
+	Role WorkspaceRole `json:"role" binding:"required"`
+}
+
+type CreateInvitationRequest struct {
+	Email string        `json:"email" binding:"required,email"`
+	Role  WorkspaceRole `json:"role" binding:"required"`
+}
+
+type InvitationResponse struct {
+	ID            uint          `json:"id"`
+	WorkspaceID   uint          `json:"workspace_id"`
+	WorkspaceName string        `json:"workspace_name,omitempty"`
+	Email         string        `json:"email"`
+	Role          WorkspaceRole `json:"role"`
+	InvitedByID   uint          `json:"invited_by_id"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
 // Pagination DTOs
 type PaginationParams struct {
 	Page      int    `form:"page"`
 	Limit     int    `form:"limit"`
 	Search    string `form:"search"`
+	Sort      string `form:"sort"`
 	SortBy    string `form:"sort_by"`
 	SortOrder string `form:"sort_order"`
+
+	// Filter expression such as "priority:high due:<7d -is:completed"
+	Query string `form:"q"`
+
+	// Cursor pagination (opt-in with pagination=cursor or by passing a cursor)
+	Pagination   string `form:"pagination"`
+	Cursor       string `form:"cursor"`
+	IncludeTotal bool   `form:"include_total"`
+
+	// Limit results to one workspace; defaults to all workspaces of the user
+	WorkspaceID uint `form:"workspace_id"`
+
+	// Filters (multi-valued fields accept repeated params or comma separated values)
+	CategoryIDs   []uint     `form:"category_id" collection_format:"csv"`
+	Priorities    []Priority `form:"priority" collection_format:"csv"`
+	Tags          []string   `form:"tag" collection_format:"csv"`
+	TagMode       string     `form:"tag_mode"`
+	Completed     *bool      `form:"completed"`
+	Overdue       bool       `form:"overdue"`
+	DueBefore     *time.Time `form:"due_before"`
+	DueAfter      *time.Time `form:"due_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+	CreatedAfter  *time.Time `form:"created_after"`
 }
 
 type PaginatedResponse struct {
@@ -70,30 +486,251 @@ type Pagination struct {
 	PerPage     int   `json:"per_page"`
 	Total       int64 `json:"total"`
 	TotalPages  int   `json:"total_pages"`
+
+	// Only set in cursor mode
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more,omitempty"`
+}
+
+// Trash item types
+const (
+	TrashTypeTodo     = "todo"
+	TrashTypeCategory = "category"
+)
+
+// RestoreTodoParams are the query parameters of POST /api/todos/:id/restore
+type RestoreTodoParams struct {
+	// RestoreCategory also restores the category of the todo if it is in the trash
+	RestoreCategory bool `form:"restore_category"`
+}
+
+// TrashParams are the query parameters of GET /api/trash
+type TrashParams struct {
+	Page        int    `form:"page"`
+	Limit       int    `form:"limit"`
+	Type        string `form:"type"`
+	WorkspaceID uint   `form:"workspace_id"`
+}
+
+// TrashItem is a deleted todo or category; Name is the title of a todo
+type TrashItem struct {
+	Type        string    `json:"type"`
+	ID          uint      `json:"id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	Name        string    `json:"name"`
+	CategoryID  *uint     `json:"category_id,omitempty"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	// PurgeAt is when the item is deleted permanently; null when the
+	// retention job is disabled
+	PurgeAt *time.Time `json:"purge_at" gorm:"-"`
 }
 
 // ToTodoResponse converts Todo model to TodoResponse DTO
 func ToTodoResponse(todo Todo) TodoResponse {
 	response := TodoResponse{
-		ID:          todo.ID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		Completed:   todo.Completed,
-		CategoryID:  todo.CategoryID,
-		Priority:    todo.Priority,
-		DueDate:     todo.DueDate,
-		CreatedAt:   todo.CreatedAt,
-		UpdatedAt:   todo.UpdatedAt,
+		ID:           todo.ID,
+		Title:        todo.Title,
+		Description:  todo.Description,
+		Completed:    todo.Completed,
+		WorkspaceID:  todo.WorkspaceID,
+		CategoryID:   todo.CategoryID,
+		Priority:     todo.Priority,
+		DueDate:      todo.DueDate,
+		AutoComplete: todo.AutoComplete,
+		SeriesID:     todo.RecurrenceSeriesID,
+		Occurrence:   todo.RecurrenceIndex,
+		Tags:         []TagResponse{},
+		Reminders:    []ReminderResponse{},
+		Items:        []TodoItemResponse{},
+		Progress:     ToTodoProgress(todo.Items),
+		CreatedAt:    todo.CreatedAt,
+		UpdatedAt:    todo.UpdatedAt,
+	}
+
+	if todo.RecurrenceRule != "" {
+		if rule, err := ParseRecurrenceRule(todo.RecurrenceRule); err == nil {
+			response.Recurrence = rule
+		}
+	}
+
+	for _, tag := range todo.Tags {
+		response.Tags = append(response.Tags, ToTagResponse(tag))
+	}
+
+	for _, reminder := range todo.Reminders {
+		response.Reminders = append(response.Reminders, ReminderResponse{
+			ID:            reminder.ID,
+			OffsetMinutes: reminder.OffsetMinutes,
+			RemindAt:      reminder.RemindAt,
+			SentAt:        reminder.SentAt,
+		})
+	}
+
+	for _, item := range todo.Items {
+		response.Items = append(response.Items, ToTodoItemResponse(item))
+	}
+
+	if todo.SearchTitle != "" {
+		response.Search = &TodoSearchMatch{
+			Rank:    todo.SearchRank,
+			Title:   todo.SearchTitle,
+			Snippet: todo.SearchSnippet,
+		}
 	}
 
 	if todo.Category != nil {
 		response.Category = &CategoryResponse{
-			ID:        todo.Category.ID,
-			Name:      todo.Category.Name,
-			Color:     todo.Category.Color,
-			CreatedAt: todo.Category.CreatedAt,
+			ID:          todo.Category.ID,
+			WorkspaceID: todo.Category.WorkspaceID,
+			Name:        todo.Category.Name,
+			Color:       todo.Category.Color,
+			CreatedAt:   todo.Category.CreatedAt,
+		}
+	}
+
+	return response
+}
+
+// ToBulkTodoResponse counts the results of a bulk operation
+func ToBulkTodoResponse(req BulkTodoRequest, results []BulkTodoResult, rolledBack bool) BulkTodoResponse {
+	response := BulkTodoResponse{
+		Action:       req.Action,
+		AllOrNothing: req.AllOrNothing,
+		RolledBack:   rolledBack,
+		Total:        len(results),
+		Results:      results,
+	}
+
+	for _, result := range results {
+		switch result.Status {
+		case BulkStatusSucceeded:
+			response.Succeeded++
+		case BulkStatusUnchanged:
+			response.Unchanged++
+		case BulkStatusFailed:
+			response.Failed++
+		}
+	}
+
+	return response
+}
+
+// ToTagResponse converts Tag model to TagResponse DTO
+func ToTagResponse(tag Tag) TagResponse {
+	return TagResponse{
+		ID:          tag.ID,
+		Name:        tag.Name,
+		Color:       tag.Color,
+		WorkspaceID: tag.WorkspaceID,
+		CreatedAt:   tag.CreatedAt,
+	}
+}
+
+// ToTodoItemResponse converts TodoItem model to TodoItemResponse DTO
+func ToTodoItemResponse(item TodoItem) TodoItemResponse {
+	return TodoItemResponse{
+		ID:        item.ID,
+		Title:     item.Title,
+		Completed: item.Completed,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+// ToTodoProgress computes the checklist progress of a todo
+func ToTodoProgress(items []TodoItem) TodoProgress {
+	progress := TodoProgress{Total: len(items)}
+	for _, item := range items {
+		if item.Completed {
+			progress.Completed++
 		}
 	}
+	progress.Label = fmt.Sprintf("%d/%d", progress.Completed, progress.Total)
+	return progress
+}
+
+// ToUserResponse converts User model to UserResponse DTO
+func ToUserResponse(user User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// ToAPIKeyResponse converts APIKey model to APIKeyResponse DTO
+func ToAPIKeyResponse(key APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// ToSavedViewResponse converts SavedView model to SavedViewResponse DTO
+func ToSavedViewResponse(view SavedView) SavedViewResponse {
+	response := SavedViewResponse{
+		ID:          view.ID,
+		Key:         view.Key,
+		BuiltIn:     view.BuiltIn(),
+		Name:        view.Name,
+		WorkspaceID: view.WorkspaceID,
+		Search:      view.Search,
+		Query:       view.Query,
+		Sort:        view.Sort,
+		Pagination:  view.Pagination,
+		Limit:       view.Limit,
+		Filters:     view.Filters(),
+	}
+
+	if !view.BuiltIn() {
+		createdAt, updatedAt := view.CreatedAt, view.UpdatedAt
+		response.CreatedAt = &createdAt
+		response.UpdatedAt = &updatedAt
+	}
+
+	return response
+}
+
+// ToWebhookResponse converts WebhookSubscription model to WebhookResponse DTO
+func ToWebhookResponse(webhook WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		ID:          webhook.ID,
+		WorkspaceID: webhook.WorkspaceID,
+		URL:         webhook.URL,
+		Events:      webhook.EventList(),
+		Active:      webhook.Active,
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+}
+
+// ToWebhookDeliveryResponse converts WebhookDelivery model to WebhookDeliveryResponse DTO
+func ToWebhookDeliveryResponse(delivery WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	// Only pending deliveries have a next attempt
+	if delivery.Status == DeliveryStatusPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
 
 	return response
 }
@@ -101,9 +738,55 @@ func ToTodoResponse(todo Todo) TodoResponse {
 // ToCategoryResponse converts Category model to CategoryResponse DTO
 func ToCategoryResponse(category Category) CategoryResponse {
 	return CategoryResponse{
-		ID:        category.ID,
-		Name:      category.Name,
-		Color:     category.Color,
-		CreatedAt: category.CreatedAt,
+		ID:          category.ID,
+		WorkspaceID: category.WorkspaceID,
+		Name:        category.Name,
+		Color:       category.Color,
+		CreatedAt:   category.CreatedAt,
+	}
+}
+
+// ToWorkspaceResponse converts Workspace model to WorkspaceResponse DTO
+func ToWorkspaceResponse(workspace Workspace, role WorkspaceRole) WorkspaceResponse {
+	return WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		OwnerID:   workspace.OwnerID,
+		Role:      role,
+		CreatedAt: workspace.CreatedAt,
+	}
+}
+
+// ToWorkspaceMemberResponse converts WorkspaceMember model to WorkspaceMemberResponse DTO
+func ToWorkspaceMemberResponse(member WorkspaceMember) WorkspaceMemberResponse {
+	response := WorkspaceMemberResponse{
+		UserID:    member.UserID,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
 	}
+
+	if member.User != nil {
+		response.Name = member.User.Name
+		response.Email = member.User.Email
+	}
+
+	return response
+}
+
+// ToInvitationResponse converts WorkspaceInvitation model to InvitationResponse DTO
+func ToInvitationResponse(invitation WorkspaceInvitation) InvitationResponse {
+	response := InvitationResponse{
+		ID:          invitation.ID,
+		WorkspaceID: invitation.WorkspaceID,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		InvitedByID: invitation.InvitedByID,
+		CreatedAt:   invitation.CreatedAt,
+	}
+
+	if invitation.Workspace != nil {
+		response.WorkspaceName = invitation.Workspace.Name
+	}
+
+	return response
 }
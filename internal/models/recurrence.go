@@ -0,0 +1,237 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyYearly  Frequency = "yearly"
+)
+
+// rruleUntilLayout is the UTC date-time format used by UNTIL in RRULE strings
+const rruleUntilLayout = "20060102T150405Z"
+
+// weekdayCodes maps RRULE weekday codes to time.Weekday
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceRule is a subset of the iCalendar RRULE: a frequency with an
+// interval, optional weekdays for weekly rules, and an optional end given
+// either as a date (until) or as a number of occurrences (count).
+type RecurrenceRule struct {
+	Frequency Frequency  `json:"frequency"`
+	Interval  int        `json:"interval,omitempty"`
+	ByWeekday []string   `json:"by_weekday,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+	Count     int        `json:"count,omitempty"`
+}
+
+// ValidateFrequency validates if the frequency value is valid
+func ValidateFrequency(f Frequency) bool {
+	return f == FrequencyDaily || f == FrequencyWeekly || f == FrequencyMonthly || f == FrequencyYearly
+}
+
+// Normalize fills in defaults and canonicalizes weekday codes
+func (r *RecurrenceRule) Normalize() {
+	r.Frequency = Frequency(strings.ToLower(strings.TrimSpace(string(r.Frequency))))
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+
+	seen := make(map[string]bool, len(r.ByWeekday))
+	days := make([]string, 0, len(r.ByWeekday))
+	for _, day := range r.ByWeekday {
+		code := strings.ToUpper(strings.TrimSpace(day))
+		if len(code) > 2 {
+			code = code[:2]
+		}
+		if !seen[code] {
+			seen[code] = true
+			days = append(days, code)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return weekdayOffset(weekdayCodes[days[i]]) < weekdayOffset(weekdayCodes[days[j]])
+	})
+	r.ByWeekday = days
+}
+
+// Validate checks a normalized rule
+func (r RecurrenceRule) Validate() error {
+	if !ValidateFrequency(r.Frequency) {
+		return errors.New("invalid recurrence frequency. Must be 'daily', 'weekly', 'monthly', or 'yearly'")
+	}
+	if r.Interval < 1 || r.Interval > 1000 {
+		return errors.New("recurrence interval must be between 1 and 1000")
+	}
+	if len(r.ByWeekday) > 0 && r.Frequency != FrequencyWeekly {
+		return errors.New("by_weekday is only supported for weekly recurrence")
+	}
+	for _, day := range r.ByWeekday {
+		if _, ok := weekdayCodes[day]; !ok {
+			return fmt.Errorf("invalid weekday %q. Must be one of MO, TU, WE, TH, FR, SA, SU", day)
+		}
+	}
+	if r.Count < 0 {
+		return errors.New("recurrence count must not be negative")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("recurrence count and until cannot be combined")
+	}
+	return nil
+}
+
+// String formats the rule as an RRULE value, e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR
+func (r RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + strings.ToUpper(string(r.Frequency))}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByWeekday) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(r.ByWeekday, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(rruleUntilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRecurrenceRule parses an RRULE value produced by RecurrenceRule.String
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, errors.New("invalid recurrence rule")
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = Frequency(strings.ToLower(val))
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+		case "BYDAY":
+			rule.ByWeekday = strings.Split(val, ",")
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			var until time.Time
+			until, err = time.Parse(rruleUntilLayout, val)
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+		if err != nil {
+			return nil, errors.New("invalid recurrence rule")
+		}
+	}
+
+	rule.Normalize()
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// Occurrences returns up to n occurrences of the rule starting at start,
+// which is always the first occurrence. Monthly and yearly rules keep the day
+// of start and fall back to the last day of shorter months (Jan 31 -> Feb 28).
+func (r RecurrenceRule) Occurrences(start time.Time, n int) []time.Time {
+	var occurrences []time.Time
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	// emit adds an occurrence and reports whether more may follow
+	emit := func(t time.Time) bool {
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		occurrences = append(occurrences, t)
+		if r.Count > 0 && len(occurrences) >= r.Count {
+			return false
+		}
+		return len(occurrences) < n
+	}
+
+	if n < 1 || !emit(start) {
+		return occurrences
+	}
+
+	if r.Frequency == FrequencyWeekly && len(r.ByWeekday) > 0 {
+		weekStart := start.AddDate(0, 0, -weekdayOffset(start.Weekday()))
+		for week := 0; ; week++ {
+			for _, day := range r.ByWeekday {
+				t := weekStart.AddDate(0, 0, 7*week*interval+weekdayOffset(weekdayCodes[day]))
+				if !t.After(start) {
+					continue
+				}
+				if !emit(t) {
+					return occurrences
+				}
+			}
+		}
+	}
+
+	for k := 1; ; k++ {
+		var t time.Time
+		switch r.Frequency {
+		case FrequencyDaily:
+			t = start.AddDate(0, 0, k*interval)
+		case FrequencyWeekly:
+			t = start.AddDate(0, 0, 7*k*interval)
+		case FrequencyMonthly:
+			t = addMonthsClamped(start, k*interval)
+		case FrequencyYearly:
+			t = addMonthsClamped(start, 12*k*interval)
+		default:
+			return occurrences
+		}
+		if !emit(t) {
+			return occurrences
+		}
+	}
+}
+
+// weekdayOffset returns the position of the weekday in a week starting on Monday
+func weekdayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// addMonthsClamped adds months to t without overflowing into the next month
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestRecurrenceRuleOccurrences(t *testing.T) {
+	until := date(2024, time.January, 3)
+
+	tests := []struct {
+		name  string
+		rule  RecurrenceRule
+		start time.Time
+		n     int
+		want  []time.Time
+	}{
+		{
+			name:  "daily",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1},
+			start: date(2024, time.January, 30),
+			n:     4,
+			want:  []time.Time{date(2024, time.January, 30), date(2024, time.January, 31), date(2024, time.February, 1), date(2024, time.February, 2)},
+		},
+		{
+			name:  "every third day",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 3},
+			start: date(2024, time.January, 1),
+			n:     3,
+			want:  []time.Time{date(2024, time.January, 1), date(2024, time.January, 4), date(2024, time.January, 7)},
+		},
+		{
+			name:  "weekly",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 1},
+			start: date(2024, time.January, 3),
+			n:     3,
+			want:  []time.Time{date(2024, time.January, 3), date(2024, time.January, 10), date(2024, time.January, 17)},
+		},
+		{
+			// The start (a Wednesday) is always the first occurrence
+			name:  "weekly on weekdays",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 1, ByWeekday: []string{"MO", "FR"}},
+			start: date(2024, time.January, 3),
+			n:     5,
+			want:  []time.Time{date(2024, time.January, 3), date(2024, time.January, 5), date(2024, time.January, 8), date(2024, time.January, 12), date(2024, time.January, 15)},
+		},
+		{
+			name:  "every other week on weekdays",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 2, ByWeekday: []string{"MO", "FR"}},
+			start: date(2024, time.January, 3),
+			n:     5,
+			want:  []time.Time{date(2024, time.January, 3), date(2024, time.January, 5), date(2024, time.January, 15), date(2024, time.January, 19), date(2024, time.January, 29)},
+		},
+		{
+			name:  "monthly on the 31st clamps to the end of shorter months",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1},
+			start: date(2024, time.January, 31),
+			n:     5,
+			want:  []time.Time{date(2024, time.January, 31), date(2024, time.February, 29), date(2024, time.March, 31), date(2024, time.April, 30), date(2024, time.May, 31)},
+		},
+		{
+			name:  "monthly in a non-leap year",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1},
+			start: date(2023, time.January, 31),
+			n:     3,
+			want:  []time.Time{date(2023, time.January, 31), date(2023, time.February, 28), date(2023, time.March, 31)},
+		},
+		{
+			name:  "quarterly across the year end",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 3},
+			start: date(2024, time.November, 30),
+			n:     3,
+			want:  []time.Time{date(2024, time.November, 30), date(2025, time.February, 28), date(2025, time.May, 30)},
+		},
+		{
+			name:  "yearly on a leap day",
+			rule:  RecurrenceRule{Frequency: FrequencyYearly, Interval: 1},
+			start: date(2024, time.February, 29),
+			n:     5,
+			want:  []time.Time{date(2024, time.February, 29), date(2025, time.February, 28), date(2026, time.February, 28), date(2027, time.February, 28), date(2028, time.February, 29)},
+		},
+		{
+			name:  "count limits the occurrences",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Count: 2},
+			start: date(2024, time.January, 1),
+			n:     10,
+			want:  []time.Time{date(2024, time.January, 1), date(2024, time.January, 2)},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Until: &until},
+			start: date(2024, time.January, 1),
+			n:     10,
+			want:  []time.Time{date(2024, time.January, 1), date(2024, time.January, 2), date(2024, time.January, 3)},
+		},
+		{
+			name:  "until before the start",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Until: &until},
+			start: date(2024, time.February, 1),
+			n:     10,
+			want:  nil,
+		},
+		{
+			name:  "no occurrences requested",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1},
+			start: date(2024, time.January, 1),
+			n:     0,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.Occurrences(tt.start, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	until := time.Date(2024, time.June, 30, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  RecurrenceRule
+	}{
+		{
+			name:  "daily",
+			value: "FREQ=DAILY",
+			want:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, ByWeekday: []string{}},
+		},
+		{
+			name:  "weekdays are sorted from Monday",
+			value: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,fr,MO",
+			want:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 2, ByWeekday: []string{"MO", "FR", "SU"}},
+		},
+		{
+			name:  "count",
+			value: "FREQ=MONTHLY;COUNT=12",
+			want:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, ByWeekday: []string{}, Count: 12},
+		},
+		{
+			name:  "until",
+			value: "FREQ=YEARLY;UNTIL=20240630T235959Z",
+			want:  RecurrenceRule{Frequency: FrequencyYearly, Interval: 1, ByWeekday: []string{}, Until: &until},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRecurrenceRule(tt.value)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseRecurrenceRule() = %+v, want %+v", *got, tt.want)
+			}
+
+			// String is the inverse of ParseRecurrenceRule
+			again, err := ParseRecurrenceRule(got.String())
+			if err != nil || !reflect.DeepEqual(again, got) {
+				t.Errorf("ParseRecurrenceRule(%q) = %+v, %v, want %+v", got.String(), again, err, got)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRuleRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "missing value", value: "FREQ", want: "invalid recurrence rule"},
+		{name: "unknown part", value: "FREQ=DAILY;BYHOUR=9", want: `unsupported recurrence rule part "BYHOUR"`},
+		{name: "unknown frequency", value: "FREQ=HOURLY", want: "invalid recurrence frequency. Must be 'daily', 'weekly', 'monthly', or 'yearly'"},
+		{name: "interval is not a number", value: "FREQ=DAILY;INTERVAL=x", want: "invalid recurrence rule"},
+		{name: "interval too large", value: "FREQ=DAILY;INTERVAL=1001", want: "recurrence interval must be between 1 and 1000"},
+		{name: "weekdays on a monthly rule", value: "FREQ=MONTHLY;BYDAY=MO", want: "by_weekday is only supported for weekly recurrence"},
+		{name: "unknown weekday", value: "FREQ=WEEKLY;BYDAY=XX", want: `invalid weekday "XX". Must be one of MO, TU, WE, TH, FR, SA, SU`},
+		{name: "negative count", value: "FREQ=DAILY;COUNT=-1", want: "recurrence count must not be negative"},
+		{name: "count and until", value: "FREQ=DAILY;COUNT=2;UNTIL=20240101T000000Z", want: "recurrence count and until cannot be combined"},
+		{name: "invalid until", value: "FREQ=DAILY;UNTIL=2024-01-01", want: "invalid recurrence rule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRecurrenceRule(tt.value)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ParseRecurrenceRule() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
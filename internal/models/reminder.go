@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Reminder fires OffsetMinutes before the due date of its todo. RemindAt is
+// kept in sync with the due date so the scheduler can query it directly.
+type Reminder struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	TodoID        uint       `json:"todo_id" gorm:"not null;uniqueIndex:idx_reminders_todo_offset"`
+	OffsetMinutes int        `json:"offset_minutes" gorm:"not null;uniqueIndex:idx_reminders_todo_offset"`
+	RemindAt      time.Time  `json:"remind_at" gorm:"not null;index"`
+	SentAt        *time.Time `json:"sent_at"`
+	// LockedUntil is the lease taken by the replica that is delivering the reminder
+	LockedUntil *time.Time `json:"-"`
+	Attempts    int        `json:"-" gorm:"default:0"`
+	LastError   string     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationship
+	Todo *Todo `json:"-" gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for Reminder model
+func (Reminder) TableName() string {
+	return "reminders"
+}
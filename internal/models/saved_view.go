@@ -0,0 +1,135 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SavedView is a named todo list query (filter, sort and pagination) that can
+// be run again with GET /api/views/:id/todos
+type SavedView struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	UserID      uint   `json:"user_id" gorm:"not null;uniqueIndex:idx_saved_views_user_name"`
+	Name        string `json:"name" gorm:"not null;size:100;uniqueIndex:idx_saved_views_user_name"`
+	WorkspaceID *uint  `json:"workspace_id" gorm:"index"`
+	Search      string `json:"search" gorm:"size:255"`
+	// Query is a filter expression in the query language of the q parameter
+	Query      string `json:"query" gorm:"size:500"`
+	Sort       string `json:"sort" gorm:"size:255"`
+	Pagination string `json:"pagination" gorm:"size:20;default:'offset'"`
+	Limit      int    `json:"limit" gorm:"default:10"`
+
+	// Structured filters of GET /api/todos; lists are stored comma separated
+	CategoryIDs   string     `json:"category_ids" gorm:"type:text"`
+	Priorities    string     `json:"priorities" gorm:"size:50"`
+	Tags          string     `json:"tags" gorm:"type:text"`
+	TagMode       string     `json:"tag_mode" gorm:"size:10"`
+	Completed     *bool      `json:"completed"`
+	Overdue       bool       `json:"overdue" gorm:"default:false"`
+	DueAfter      *time.Time `json:"due_after"`
+	DueBefore     *time.Time `json:"due_before"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Key identifies a built-in view; built-in views are not stored
+	Key string `json:"key" gorm:"-"`
+
+	// Relationship
+	User      *User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Workspace *Workspace `json:"-" gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for SavedView model
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+// BuiltIn reports whether the view is one of BuiltInViews
+func (v SavedView) BuiltIn() bool {
+	return v.Key != ""
+}
+
+// Filters returns the structured filters stored in the view
+func (v SavedView) Filters() TodoFilters {
+	filters := TodoFilters{
+		CategoryIDs:   []uint{},
+		Priorities:    []Priority{},
+		Tags:          splitList(v.Tags),
+		TagMode:       v.TagMode,
+		Completed:     v.Completed,
+		Overdue:       v.Overdue,
+		DueAfter:      v.DueAfter,
+		DueBefore:     v.DueBefore,
+		CreatedAfter:  v.CreatedAfter,
+		CreatedBefore: v.CreatedBefore,
+	}
+	for _, id := range splitList(v.CategoryIDs) {
+		if categoryID, err := strconv.ParseUint(id, 10, 32); err == nil {
+			filters.CategoryIDs = append(filters.CategoryIDs, uint(categoryID))
+		}
+	}
+	for _, priority := range splitList(v.Priorities) {
+		filters.Priorities = append(filters.Priorities, Priority(priority))
+	}
+	return filters
+}
+
+// SetFilters replaces the structured filters stored in the view
+func (v *SavedView) SetFilters(filters TodoFilters) {
+	categoryIDs := make([]string, 0, len(filters.CategoryIDs))
+	for _, id := range filters.CategoryIDs {
+		categoryIDs = append(categoryIDs, strconv.FormatUint(uint64(id), 10))
+	}
+	priorities := make([]string, 0, len(filters.Priorities))
+	for _, priority := range filters.Priorities {
+		priorities = append(priorities, string(priority))
+	}
+
+	v.CategoryIDs = strings.Join(categoryIDs, ",")
+	v.Priorities = strings.Join(priorities, ",")
+	v.Tags = strings.Join(filters.Tags, ",")
+	v.TagMode = filters.TagMode
+	v.Completed = filters.Completed
+	v.Overdue = filters.Overdue
+	v.DueAfter = filters.DueAfter
+	v.DueBefore = filters.DueBefore
+	v.CreatedAfter = filters.CreatedAfter
+	v.CreatedBefore = filters.CreatedBefore
+}
+
+// Params returns the todo list parameters stored in the view
+func (v SavedView) Params() PaginationParams {
+	params := v.Filters().Params()
+	params.Search = v.Search
+	params.Query = v.Query
+	params.Sort = v.Sort
+	params.Pagination = v.Pagination
+	params.Limit = v.Limit
+	if v.WorkspaceID != nil {
+		params.WorkspaceID = *v.WorkspaceID
+	}
+	return params
+}
+
+// splitList splits a comma separated column; an empty column is an empty list
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// BuiltInViews returns the views every user has. Their dates are evaluated
+// when the view is run.
+func BuiltInViews() []SavedView {
+	return []SavedView{
+		{Key: "today", Name: "Today", Query: "due:today -is:completed", Sort: "due_date,-priority", Pagination: "offset", Limit: 50},
+		{Key: "upcoming", Name: "Upcoming 7 days", Query: "due:7d -is:completed", Sort: "due_date,-priority", Pagination: "offset", Limit: 50},
+		{Key: "overdue", Name: "Overdue", Query: "is:overdue", Sort: "due_date,-priority", Pagination: "offset", Limit: 50},
+		{Key: "high-priority", Name: "High priority", Query: "priority:high -is:completed", Sort: "due_date,-created_at", Pagination: "offset", Limit: 50},
+	}
+}
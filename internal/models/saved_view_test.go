@@ -0,0 +1,92 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTodoFiltersRoundTrip(t *testing.T) {
+	completed := false
+	dueBefore := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	createdAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filters TodoFilters
+	}{
+		{
+			name: "no filters",
+			filters: TodoFilters{
+				CategoryIDs: []uint{},
+				Priorities:  []Priority{},
+				Tags:        []string{},
+			},
+		},
+		{
+			name: "every filter",
+			filters: TodoFilters{
+				CategoryIDs:  []uint{3, 12},
+				Priorities:   []Priority{PriorityHigh, PriorityMedium},
+				Tags:         []string{"home", "waiting on"},
+				TagMode:      "all",
+				Completed:    &completed,
+				Overdue:      true,
+				DueBefore:    &dueBefore,
+				CreatedAfter: &createdAfter,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var view SavedView
+			view.SetFilters(tt.filters)
+
+			if got := view.Filters(); !reflect.DeepEqual(got, tt.filters) {
+				t.Errorf("Filters() = %+v, want %+v", got, tt.filters)
+			}
+		})
+	}
+}
+
+func TestSavedViewParams(t *testing.T) {
+	completed := true
+	dueAfter := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	workspaceID := uint(4)
+
+	view := SavedView{
+		WorkspaceID: &workspaceID,
+		Search:      "milk",
+		Query:       "has:due",
+		Sort:        "-priority",
+		Pagination:  "cursor",
+		Limit:       25,
+	}
+	view.SetFilters(TodoFilters{
+		CategoryIDs: []uint{7},
+		Priorities:  []Priority{PriorityLow},
+		Tags:        []string{"errand"},
+		TagMode:     "any",
+		Completed:   &completed,
+		DueAfter:    &dueAfter,
+	})
+
+	want := PaginationParams{
+		WorkspaceID: 4,
+		Search:      "milk",
+		Query:       "has:due",
+		Sort:        "-priority",
+		Pagination:  "cursor",
+		Limit:       25,
+		CategoryIDs: []uint{7},
+		Priorities:  []Priority{PriorityLow},
+		Tags:        []string{"errand"},
+		TagMode:     "any",
+		Completed:   &completed,
+		DueAfter:    &dueAfter,
+	}
+	if got := view.Params(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type Tag struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint      `json:"workspace_id" gorm:"not null;uniqueIndex:idx_tags_workspace_name"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex:idx_tags_workspace_name"`
+	Color       string    `json:"color" gorm:"default:'#6B7280'"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationship
+	Workspace *Workspace `json:"-" gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for Tag model
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TodoTag is the join table between todos and tags
+type TodoTag struct {
+	TodoID    uint `gorm:"primaryKey"`
+	TagID     uint `gorm:"primaryKey;index"`
+	CreatedAt time.Time
+
+	// Relationship
+	Todo *Todo `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE"`
+	Tag  *Tag  `gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for TodoTag model
+func (TodoTag) TableName() string {
+	return "todo_tags"
+}
+
+// NormalizeTagName trims and lowercases a tag name so that "Urgent" and
+// "urgent " refer to the same tag
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidateTagName validates a normalized tag name. Commas are not allowed
+// because tag filters are comma separated.
+func ValidateTagName(name string) bool {
+	return name != "" && len(name) <= 50 && !strings.Contains(name, ",")
+}
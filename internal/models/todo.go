@@ -15,19 +15,39 @@ const (
 )
 
 type Todo struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Title       string         `json:"title" gorm:"not null"`
-	Description string         `json:"description"`
-	Completed   bool           `json:"completed" gorm:"default:false"`
-	CategoryID  uint           `json:"category_id" gorm:"not null"`
-	Priority    Priority       `json:"priority" gorm:"default:'medium'"`
-	DueDate     *time.Time     `json:"due_date"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint   `json:"workspace_id" gorm:"index"`
+	UserID      uint   `json:"user_id" gorm:"index"`
+	Title       string `json:"title" gorm:"not null"`
+	Description string `json:"description"`
+	Completed   bool   `json:"completed" gorm:"default:false"`
+	// AutoComplete marks the todo completed when all checklist items are checked
+	AutoComplete bool       `json:"auto_complete" gorm:"default:false"`
+	CategoryID   uint       `json:"category_id" gorm:"not null"`
+	Priority     Priority   `json:"priority" gorm:"default:'medium'"`
+	DueDate      *time.Time `json:"due_date"`
+	// Recurrence is stored as an RRULE value; occurrences are computed from
+	// RecurrenceStart and numbered with RecurrenceIndex within the series
+	RecurrenceRule     string         `json:"recurrence_rule" gorm:"size:255"`
+	RecurrenceStart    *time.Time     `json:"recurrence_start"`
+	RecurrenceSeriesID *uint          `json:"recurrence_series_id" gorm:"index"`
+	RecurrenceIndex    int            `json:"recurrence_index" gorm:"default:0"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Filled by full-text search queries only
+	SearchRank    float64 `json:"-" gorm:"->;-:migration"`
+	SearchTitle   string  `json:"-" gorm:"->;-:migration"`
+	SearchSnippet string  `json:"-" gorm:"->;-:migration"`
 
 	// Relationship
-	Category *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Workspace *Workspace `json:"-" gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`
+	User      *User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+	Category  *Category  `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Items     []TodoItem `json:"items,omitempty" gorm:"foreignKey:TodoID"`
+	Tags      []Tag      `json:"tags,omitempty" gorm:"many2many:todo_tags"`
+	Reminders []Reminder `json:"reminders,omitempty" gorm:"foreignKey:TodoID"`
 }
 
 // TableName specifies the table name for Todo model
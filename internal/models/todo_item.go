@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TodoItem is a checklist item (subtask) of a todo
+type TodoItem struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TodoID    uint      `json:"todo_id" gorm:"not null;index"`
+	Title     string    `json:"title" gorm:"not null"`
+	Completed bool      `json:"completed" gorm:"default:false"`
+	Position  int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationship
+	Todo *Todo `json:"-" gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for TodoItem model
+func (TodoItem) TableName() string {
+	return "todo_items"
+}
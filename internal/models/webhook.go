@@ -0,0 +1,124 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook event types
+const (
+	EventTodoCreated      = "todo.created"
+	EventTodoUpdated      = "todo.updated"
+	EventTodoCompleted    = "todo.completed"
+	EventTodoDeleted      = "todo.deleted"
+	EventTodoRestored     = "todo.restored"
+	EventTodoPurged       = "todo.purged"
+	EventCategoryCreated  = "category.created"
+	EventCategoryUpdated  = "category.updated"
+	EventCategoryDeleted  = "category.deleted"
+	EventCategoryRestored = "category.restored"
+	EventCategoryPurged   = "category.purged"
+
+	// EventAll subscribes to every event
+	EventAll = "*"
+)
+
+// WebhookEvents lists the events a subscription can filter on
+var WebhookEvents = []string{
+	EventTodoCreated,
+	EventTodoUpdated,
+	EventTodoCompleted,
+	EventTodoDeleted,
+	EventTodoRestored,
+	EventTodoPurged,
+	EventCategoryCreated,
+	EventCategoryUpdated,
+	EventCategoryDeleted,
+	EventCategoryRestored,
+	EventCategoryPurged,
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// WebhookSubscription sends the events of a workspace to a URL. The secret is
+// kept in plain text because it is needed to sign every delivery.
+type WebhookSubscription struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint      `json:"workspace_id" gorm:"not null;index"`
+	UserID      uint      `json:"user_id" gorm:"index"`
+	URL         string    `json:"url" gorm:"not null"`
+	Events      string    `json:"events" gorm:"not null"`
+	Secret      string    `json:"-" gorm:"not null"`
+	Active      bool      `json:"active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationship
+	Workspace *Workspace `json:"-" gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`
+	User      *User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+}
+
+// TableName specifies the table name for WebhookSubscription model
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// EventList returns the subscribed events as a slice
+func (w WebhookSubscription) EventList() []string {
+	return strings.Split(w.Events, ",")
+}
+
+// Matches reports whether the subscription wants the event
+func (w WebhookSubscription) Matches(event string) bool {
+	for _, e := range w.EventList() {
+		if e == EventAll || e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWebhookEvent validates if the event value is valid
+func ValidateWebhookEvent(event string) bool {
+	if event == EventAll {
+		return true
+	}
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event for one subscription. Deliveries are written in
+// the same transaction as the change that caused the event (transactional
+// outbox) and sent by the dispatcher, so events survive a crash.
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	SubscriptionID uint       `json:"subscription_id" gorm:"not null;index"`
+	EventID        string     `json:"event_id" gorm:"not null;index"`
+	Event          string     `json:"event" gorm:"not null"`
+	Payload        string     `json:"-" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"not null;default:'pending'"`
+	Attempts       int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationship
+	Subscription *WebhookSubscription `json:"-" gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for WebhookDelivery model
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
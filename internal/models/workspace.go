@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleEditor WorkspaceRole = "editor"
+	WorkspaceRoleViewer WorkspaceRole = "viewer"
+)
+
+// Workspace owns categories and todos and is shared between its members
+type Workspace struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"not null"`
+	OwnerID   *uint          `json:"owner_id" gorm:"index"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relationship
+	Owner   *User             `json:"-" gorm:"foreignKey:OwnerID;constraint:OnDelete:SET NULL"`
+	Members []WorkspaceMember `json:"-" gorm:"foreignKey:WorkspaceID"`
+}
+
+// TableName specifies the table name for Workspace model
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+type WorkspaceMember struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint          `json:"workspace_id" gorm:"not null;uniqueIndex:idx_workspace_members_workspace_user"`
+	UserID      uint          `json:"user_id" gorm:"not null;uniqueIndex:idx_workspace_members_workspace_user;index"`
+	Role        WorkspaceRole `json:"role" gorm:"not null"`
+	CreatedAt   time.Time     `json:"created_at"`
+
+	// Relationship
+	Workspace *Workspace `json:"-" gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`
+	User      *User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for WorkspaceMember model
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
+
+// WorkspaceInvitation invites a user by email to join a workspace
+type WorkspaceInvitation struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint          `json:"workspace_id" gorm:"not null;index"`
+	Email       string        `json:"email" gorm:"not null;index"`
+	Role        WorkspaceRole `json:"role" gorm:"not null"`
+	InvitedByID uint          `json:"invited_by_id" gorm:"not null"`
+	AcceptedAt  *time.Time    `json:"accepted_at"`
+	DeclinedAt  *time.Time    `json:"declined_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+
+	// Relationship
+	Workspace *Workspace `json:"-" gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`
+	InvitedBy *User      `json:"-" gorm:"foreignKey:InvitedByID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for WorkspaceInvitation model
+func (WorkspaceInvitation) TableName() string {
+	return "workspace_invitations"
+}
+
+// ValidateWorkspaceRole validates if the role value is valid
+func ValidateWorkspaceRole(r WorkspaceRole) bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleEditor || r == WorkspaceRoleViewer
+}
+
+// CanWrite reports whether the role may create, update or delete content
+func (r WorkspaceRole) CanWrite() bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleEditor
+}
+
+// CanManage reports whether the role may manage the workspace and its members
+func (r WorkspaceRole) CanManage() bool {
+	return r == WorkspaceRoleOwner
+}
@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/config"
+)
+
+// Notification is a due-date reminder for a single todo
+type Notification struct {
+	ReminderID uint      `json:"reminder_id"`
+	TodoID     uint      `json:"todo_id"`
+	TodoTitle  string    `json:"todo_title"`
+	DueDate    time.Time `json:"due_date"`
+	RemindAt   time.Time `json:"remind_at"`
+	UserID     uint      `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	UserEmail  string    `json:"user_email"`
+}
+
+// Notifier delivers reminders to users. Implementations must be safe for
+// concurrent use.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Multi delivers a notification through every notifier and joins the errors
+type Multi []Notifier
+
+func (m Multi) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogNotifier only writes reminders to the log. It is used when no other
+// notifier is configured.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, n Notification) error {
+	log.Printf("Reminder for todo %d %q (user %d) due at %s", n.TodoID, n.TodoTitle, n.UserID, n.DueDate.Format(time.RFC3339))
+	return nil
+}
+
+// FromConfig builds the notifiers enabled in the configuration: email when
+// SMTP_HOST is set and webhook when REMINDER_WEBHOOK_URL is set
+func FromConfig(cfg *config.Config) Notifier {
+	var notifiers Multi
+
+	if cfg.SMTPHost != "" {
+		notifiers = append(notifiers, NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom))
+	}
+	if cfg.ReminderWebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.ReminderWebhookURL, cfg.ReminderWebhookSecret))
+	}
+
+	switch len(notifiers) {
+	case 0:
+		return LogNotifier{}
+	case 1:
+		return notifiers[0]
+	default:
+		return notifiers
+	}
+}
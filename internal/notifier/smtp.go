@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPNotifier sends reminders by email. Without a username no
+// authentication is used, which works with local stand-ins like MailHog.
+type SMTPNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPNotifier(host, port, username, password, from string) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPNotifier{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (s *SMTPNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.UserEmail == "" {
+		return fmt.Errorf("user %d has no email address", n.UserID)
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{n.UserEmail}, s.message(n)); err != nil {
+		return fmt.Errorf("failed to send reminder email: %w", err)
+	}
+	return nil
+}
+
+// message builds a plain text email for the reminder
+func (s *SMTPNotifier) message(n Notification) []byte {
+	subject := headerValue("Reminder: " + n.TodoTitle)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(n.UserEmail))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "Hi %s,\r\n\r\n", n.UserName)
+	fmt.Fprintf(&b, "Your todo %q is due at %s.\r\n", n.TodoTitle, n.DueDate.UTC().Format("2006-01-02 15:04 MST"))
+
+	return []byte(b.String())
+}
+
+// headerValue strips line breaks so user input cannot inject email headers
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
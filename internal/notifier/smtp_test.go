@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpMessage is an email received by the SMTP stand-in
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer runs a minimal SMTP server that accepts every message and
+// sends it to the returned channel
+func startSMTPServer(t *testing.T) (host, port string, messages <-chan smtpMessage) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan smtpMessage, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, received)
+		}
+	}()
+
+	host, port, _ = net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func serveSMTP(conn net.Conn, received chan<- smtpMessage) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+
+	var msg smtpMessage
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL":
+			msg.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			msg.to = append(msg.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = string(data)
+			text.PrintfLine("250 OK")
+			received <- msg
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	host, port, messages := startSMTPServer(t)
+	n := NewSMTPNotifier(host, port, "", "", "todo-list@localhost")
+
+	err := n.Notify(context.Background(), Notification{
+		TodoID:    1,
+		TodoTitle: "Pay rent\r\nBcc: everyone@example.com",
+		DueDate:   time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+		UserID:    1,
+		UserName:  "Budi",
+		UserEmail: "budi@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	var msg smtpMessage
+	select {
+	case msg = <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	if msg.from != "todo-list@localhost" || len(msg.to) != 1 || msg.to[0] != "budi@example.com" {
+		t.Errorf("envelope = %s -> %v", msg.from, msg.to)
+	}
+
+	header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(msg.data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("invalid message %q: %v", msg.data, err)
+	}
+	if got := header.Get("Subject"); got != "Reminder: Pay rent  Bcc: everyone@example.com" {
+		t.Errorf("Subject = %q", got)
+	}
+	if got := header.Get("Bcc"); got != "" {
+		t.Errorf("Bcc = %q, want no injected header", got)
+	}
+	if !strings.Contains(msg.data, "Hi Budi,") || !strings.Contains(msg.data, "is due at 2024-01-31 09:00 UTC.") {
+		t.Errorf("body = %q", msg.data)
+	}
+}
+
+func TestSMTPNotifierRequiresEmail(t *testing.T) {
+	n := NewSMTPNotifier("127.0.0.1", "1", "", "", "todo-list@localhost")
+
+	if err := n.Notify(context.Background(), Notification{UserID: 1}); err == nil {
+		t.Error("Notify() without an email address succeeded")
+	}
+}
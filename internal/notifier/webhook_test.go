@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan request, 1)
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code := status
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{header: r.Header, body: body}
+		w.WriteHeader(code)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, "webhook-secret")
+	notification := Notification{ReminderID: 3, TodoID: 1, TodoTitle: "Pay rent", UserID: 2}
+
+	if err := n.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	req := <-requests
+
+	if got, want := req.header.Get("X-Signature-256"), "sha256="+Sign("webhook-secret", req.body); got != want {
+		t.Errorf("X-Signature-256 = %q, want %q", got, want)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("invalid body %s: %v", req.body, err)
+	}
+	if payload.Event != "todo.reminder" || payload.Notification.ReminderID != 3 || payload.Notification.TodoTitle != "Pay rent" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	t.Run("no secret", func(t *testing.T) {
+		if err := NewWebhookNotifier(server.URL, "").Notify(context.Background(), notification); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+		if got := (<-requests).header.Get("X-Signature-256"); got != "" {
+			t.Errorf("X-Signature-256 = %q, want none", got)
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		if err := n.Notify(context.Background(), notification); err == nil {
+			t.Error("Notify() succeeded on status 500")
+		}
+		<-requests
+	})
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 test case 2 of RFC 4231
+	got := Sign("Jefe", []byte("what do ya want for nothing?"))
+	if want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"; got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
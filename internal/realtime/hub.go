@@ -0,0 +1,165 @@
+package realtime
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultReplaySize is the number of recent events kept for Last-Event-ID resume
+	DefaultReplaySize = 1000
+
+	// subscriberBuffer is the number of events queued for a slow client before
+	// it is disconnected. The client then reconnects and resumes from the
+	// replay buffer.
+	subscriberBuffer = 64
+)
+
+// Event is a change pushed to connected clients. IDs come from a database
+// sequence, so they increase across every server instance. Ephemeral events
+// such as presence updates have no ID and are not kept for replay.
+type Event struct {
+	ID          int64           `json:"id,omitempty"`
+	Type        string          `json:"event"`
+	WorkspaceID uint            `json:"workspace_id"`
+	CategoryID  uint            `json:"category_id,omitempty"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Hub fans events out to the subscribers of this server instance and keeps a
+// bounded buffer of recent events for clients that reconnect.
+type Hub struct {
+	mu          sync.Mutex
+	size        int
+	buffer      []Event
+	subscribers map[*Subscription]struct{}
+
+	// lastID numbers events when there is no database sequence
+	lastID int64
+
+	// presence holds who is viewing or editing each todo, keyed by connection
+	presence map[uint]map[string]PresenceUpdate
+}
+
+// Subscription receives the events broadcast after it was created. C is
+// closed when the subscription is closed or falls too far behind.
+type Subscription struct {
+	C <-chan Event
+
+	ch  chan Event
+	hub *Hub
+}
+
+var hub = NewHub(DefaultReplaySize)
+
+func NewHub(size int) *Hub {
+	if size < 1 {
+		size = DefaultReplaySize
+	}
+	return &Hub{
+		size:        size,
+		subscribers: make(map[*Subscription]struct{}),
+		lastID:      time.Now().UnixMicro(),
+		presence:    make(map[uint]map[string]PresenceUpdate),
+	}
+}
+
+// nextID returns a local event ID. IDs start at the current time, so they
+// keep increasing across restarts.
+func (h *Hub) nextID() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastID++
+	return h.lastID
+}
+
+// GetHub returns the hub shared by the server
+func GetHub() *Hub {
+	return hub
+}
+
+// Broadcast stores the event in the replay buffer and sends it to every
+// subscriber. Events are kept sorted by ID, so an event that arrives late
+// (its transaction committed after a newer one) is still replayed in order.
+// Ephemeral events are only sent to the current subscribers.
+func (h *Hub) Broadcast(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if event.ID != 0 {
+		if !h.store(event) {
+			return
+		}
+	}
+	h.send(event)
+}
+
+// store adds an event to the replay buffer and reports whether it is new
+func (h *Hub) store(event Event) bool {
+	i := sort.Search(len(h.buffer), func(i int) bool { return h.buffer[i].ID >= event.ID })
+	if i < len(h.buffer) && h.buffer[i].ID == event.ID {
+		return false
+	}
+	h.buffer = append(h.buffer, Event{})
+	copy(h.buffer[i+1:], h.buffer[i:])
+	h.buffer[i] = event
+	if len(h.buffer) > h.size {
+		h.buffer = h.buffer[len(h.buffer)-h.size:]
+	}
+	return true
+}
+
+// send delivers an event to every subscriber
+func (h *Hub) send(event Event) {
+	for sub := range h.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// Too slow: drop the client instead of blocking everyone else
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. When lastEventID is set, the buffered
+// events after it are returned for replay. complete is false when events
+// after lastEventID may be missing from the buffer, in which case the client
+// has to reload its data.
+func (h *Hub) Subscribe(lastEventID int64) (sub *Subscription, replay []Event, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	sub = &Subscription{C: ch, ch: ch, hub: h}
+	h.subscribers[sub] = struct{}{}
+
+	if lastEventID <= 0 {
+		return sub, nil, true
+	}
+
+	// The replay is only known to be complete while the buffer still reaches
+	// back to the last event the client saw
+	complete = len(h.buffer) > 0 && h.buffer[0].ID <= lastEventID
+
+	i := sort.Search(len(h.buffer), func(i int) bool { return h.buffer[i].ID > lastEventID })
+	replay = append([]Event(nil), h.buffer[i:]...)
+
+	return sub, replay, complete
+}
+
+// Close unregisters the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.ch)
+}
@@ -0,0 +1,69 @@
+package realtime
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// pendingKey is the context key of the events held back by Transaction
+type pendingKey struct{}
+
+// pendingEvents are the events published in a transaction that has not
+// committed yet
+type pendingEvents struct {
+	events []Event
+}
+
+// Transaction runs fn in a transaction like gorm.DB.Transaction. Without
+// LISTEN/NOTIFY the events published in fn are held back and broadcast to the
+// hub once the transaction commits, so clients never see a change that was
+// rolled back. Nested calls run in a savepoint and pass their events to the
+// enclosing transaction only when they succeed.
+//
+// On Postgres the notifications are already tied to the transaction, so this
+// is the same as db.Transaction.
+func Transaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if isPostgres(db) {
+		return db.Transaction(fn)
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parent, _ := ctx.Value(pendingKey{}).(*pendingEvents)
+
+	pending := &pendingEvents{}
+	if err := db.WithContext(context.WithValue(ctx, pendingKey{}, pending)).Transaction(fn); err != nil {
+		return err
+	}
+
+	if parent != nil {
+		parent.events = append(parent.events, pending.events...)
+		return nil
+	}
+	for _, event := range pending.events {
+		broadcastLocal(event)
+	}
+	return nil
+}
+
+// publishLocal holds the event back until the transaction started by
+// Transaction commits, or broadcasts it right away outside of one
+func publishLocal(tx *gorm.DB, event Event) {
+	if tx.Statement.Context != nil {
+		if pending, ok := tx.Statement.Context.Value(pendingKey{}).(*pendingEvents); ok {
+			pending.events = append(pending.events, event)
+			return
+		}
+	}
+	broadcastLocal(event)
+}
+
+// broadcastLocal numbers the event and broadcasts it to the hub. Events are
+// numbered when they are sent, so the IDs follow the order of the commits.
+func broadcastLocal(event Event) {
+	event.ID = hub.nextID()
+	hub.Broadcast(event)
+}
@@ -0,0 +1,122 @@
+package realtime
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db
+}
+
+// received returns the events the subscription got so far
+func received(sub *Subscription) []string {
+	var types []string
+	for {
+		select {
+		case event := <-sub.C:
+			types = append(types, event.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestTransactionBroadcastsAfterCommit(t *testing.T) {
+	db := openTestDB(t)
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		fn      func(t *testing.T, sub *Subscription) func(tx *gorm.DB) error
+		wantErr error
+		want    []string
+	}{
+		{
+			name: "commit",
+			fn: func(t *testing.T, sub *Subscription) func(tx *gorm.DB) error {
+				return func(tx *gorm.DB) error {
+					if err := Publish(tx, Event{Type: "todo.created"}, 1, nil); err != nil {
+						return err
+					}
+					if got := received(sub); len(got) != 0 {
+						t.Errorf("events before commit = %q", got)
+					}
+					return Publish(tx, Event{Type: "todo.updated"}, 1, nil)
+				}
+			},
+			want: []string{"todo.created", "todo.updated"},
+		},
+		{
+			name: "rollback",
+			fn: func(t *testing.T, sub *Subscription) func(tx *gorm.DB) error {
+				return func(tx *gorm.DB) error {
+					if err := Publish(tx, Event{Type: "todo.updated"}, 1, nil); err != nil {
+						return err
+					}
+					return errFailed
+				}
+			},
+			wantErr: errFailed,
+		},
+		{
+			name: "savepoints",
+			fn: func(t *testing.T, sub *Subscription) func(tx *gorm.DB) error {
+				return func(tx *gorm.DB) error {
+					failed := Transaction(tx, func(tx *gorm.DB) error {
+						if err := Publish(tx, Event{Type: "todo.deleted"}, 1, nil); err != nil {
+							return err
+						}
+						return errFailed
+					})
+					if !errors.Is(failed, errFailed) {
+						t.Errorf("nested Transaction() error = %v", failed)
+					}
+					return Transaction(tx, func(tx *gorm.DB) error {
+						return Publish(tx, Event{Type: "todo.completed"}, 2, nil)
+					})
+				}
+			},
+			want: []string{"todo.completed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, _, _ := hub.Subscribe(0)
+			defer sub.Close()
+
+			err := Transaction(db, tt.fn(t, sub))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transaction() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := received(sub); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishWithoutTransactionBroadcastsRightAway(t *testing.T) {
+	db := openTestDB(t)
+
+	sub, _, _ := hub.Subscribe(0)
+	defer sub.Close()
+
+	if err := Publish(db, Event{Type: "category.updated"}, 1, nil); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if got := received(sub); len(got) != 1 || got[0] != "category.updated" {
+		t.Errorf("events = %q, want [category.updated]", got)
+	}
+}
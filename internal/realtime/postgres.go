@@ -0,0 +1,122 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	// Channel is the Postgres NOTIFY channel carrying events between instances
+	Channel = "realtime_events"
+
+	// PresenceChannel carries presence updates between instances
+	PresenceChannel = "realtime_presence"
+
+	// SequenceName generates event IDs
+	SequenceName = "realtime_event_id_seq"
+
+	// maxPayloadSize stays below the 8000 byte limit of NOTIFY payloads
+	maxPayloadSize = 7900
+
+	listenRetryDelay = 5 * time.Second
+)
+
+// notify sends an event to every server instance with pg_notify. Postgres
+// only delivers the notification when the transaction of tx commits. Events
+// that are too large for a notification are sent with only the ID of the
+// changed record.
+func notify(tx *gorm.DB, event Event, recordID uint) error {
+	if err := tx.Raw("SELECT nextval(?)", SequenceName).Scan(&event.ID).Error; err != nil {
+		return fmt.Errorf("failed to get event id: %w", err)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if len(payload) > maxPayloadSize {
+		event.Data = json.RawMessage(fmt.Sprintf(`{"id":%d}`, recordID))
+		if payload, err = json.Marshal(event); err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+
+	if err := tx.Exec("SELECT pg_notify(?, ?)", Channel, string(payload)).Error; err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// notifyPresence sends a presence update to every server instance
+func notifyPresence(db *gorm.DB, update PresenceUpdate) error {
+	payload, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to encode presence: %w", err)
+	}
+	if err := db.Exec("SELECT pg_notify(?, ?)", PresenceChannel, string(payload)).Error; err != nil {
+		return fmt.Errorf("failed to publish presence: %w", err)
+	}
+	return nil
+}
+
+// Listen receives events published by any server instance and broadcasts
+// them to the hub until the context is cancelled. It uses its own connection,
+// since LISTEN is bound to a session, and reconnects when it is lost.
+func Listen(ctx context.Context, dsn string, hub *Hub) {
+	for {
+		if err := listen(ctx, dsn, hub); err != nil && ctx.Err() == nil {
+			log.Printf("Realtime listener: %v (retrying in %s)", err, listenRetryDelay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func listen(ctx context.Context, dsn string, hub *Hub) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	for _, channel := range []string{Channel, PresenceChannel} {
+		if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+	}
+	log.Printf("Realtime listener started (channels %s, %s)", Channel, PresenceChannel)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		switch notification.Channel {
+		case Channel:
+			var event Event
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				log.Printf("Realtime listener: invalid event: %v", err)
+				continue
+			}
+			hub.Broadcast(event)
+		case PresenceChannel:
+			var update PresenceUpdate
+			if err := json.Unmarshal([]byte(notification.Payload), &update); err != nil {
+				log.Printf("Realtime listener: invalid presence: %v", err)
+				continue
+			}
+			hub.ApplyPresence(update)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package realtime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Presence states
+const (
+	PresenceViewing = "viewing"
+	PresenceEditing = "editing"
+	PresenceIdle    = "idle"
+)
+
+const (
+	// PresenceEventType is the type of the ephemeral events sent when the
+	// users on a todo change
+	PresenceEventType = "presence"
+
+	// PresenceTTL is how long a presence lasts without being refreshed, so
+	// entries of crashed instances disappear on their own
+	PresenceTTL = time.Minute
+)
+
+// instanceID tells the connections of this server apart from other instances
+var instanceID = newInstanceID()
+
+var connections atomic.Uint64
+
+// PresenceUpdate is the presence of one connection on one todo. An idle
+// state removes the presence.
+type PresenceUpdate struct {
+	Instance    string    `json:"instance"`
+	Connection  uint64    `json:"connection"`
+	TodoID      uint      `json:"todo_id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	CategoryID  uint      `json:"category_id"`
+	UserID      uint      `json:"user_id"`
+	UserName    string    `json:"user_name"`
+	State       string    `json:"state"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// PresenceUser is a user currently on a todo
+type PresenceUser struct {
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	State  string `json:"state"`
+}
+
+// PresenceData is the data of a presence event
+type PresenceData struct {
+	TodoID uint           `json:"todo_id"`
+	Users  []PresenceUser `json:"users"`
+}
+
+// ValidatePresenceState validates if the presence state is valid
+func ValidatePresenceState(state string) bool {
+	return state == PresenceViewing || state == PresenceEditing || state == PresenceIdle
+}
+
+// NewPresence returns a presence update for a new connection of this instance
+func NewPresence(userID uint, userName string) PresenceUpdate {
+	return PresenceUpdate{
+		Instance:   instanceID,
+		Connection: connections.Add(1),
+		UserID:     userID,
+		UserName:   userName,
+	}
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func (u PresenceUpdate) key() string {
+	return fmt.Sprintf("%s/%d", u.Instance, u.Connection)
+}
+
+// ApplyPresence records a presence update and sends the users on the todo to
+// the subscribers
+func (h *Hub) ApplyPresence(update PresenceUpdate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := h.presence[update.TodoID]
+	if update.State == PresenceIdle || !update.ExpiresAt.After(time.Now()) {
+		if _, ok := entries[update.key()]; !ok {
+			return
+		}
+		delete(entries, update.key())
+		if len(entries) == 0 {
+			delete(h.presence, update.TodoID)
+		}
+	} else {
+		if entries == nil {
+			entries = make(map[string]PresenceUpdate)
+			h.presence[update.TodoID] = entries
+		}
+		previous, ok := entries[update.key()]
+		entries[update.key()] = update
+		// Refreshes that change nothing are not sent again
+		if ok && previous.State == update.State {
+			return
+		}
+	}
+
+	h.sendPresence(update.TodoID, update.WorkspaceID, update.CategoryID)
+}
+
+// SweepPresence removes presence entries that were not refreshed in time
+func (h *Hub) SweepPresence(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for todoID, entries := range h.presence {
+		var expired *PresenceUpdate
+		for key, entry := range entries {
+			if !entry.ExpiresAt.After(now) {
+				delete(entries, key)
+				expired = &entry
+			}
+		}
+		if len(entries) == 0 {
+			delete(h.presence, todoID)
+		}
+		if expired != nil {
+			h.sendPresence(todoID, expired.WorkspaceID, expired.CategoryID)
+		}
+	}
+}
+
+// sendPresence sends the users on a todo. A user with several connections is
+// listed once, as editing if any of them is editing.
+func (h *Hub) sendPresence(todoID, workspaceID, categoryID uint) {
+	users := make(map[uint]PresenceUser)
+	for _, entry := range h.presence[todoID] {
+		user, ok := users[entry.UserID]
+		if !ok || entry.State == PresenceEditing {
+			user = PresenceUser{UserID: entry.UserID, Name: entry.UserName, State: entry.State}
+		}
+		users[entry.UserID] = user
+	}
+
+	data := PresenceData{TodoID: todoID, Users: make([]PresenceUser, 0, len(users))}
+	for _, user := range users {
+		data.Users = append(data.Users, user)
+	}
+	sort.Slice(data.Users, func(i, j int) bool { return data.Users[i].UserID < data.Users[j].UserID })
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	h.send(Event{Type: PresenceEventType, WorkspaceID: workspaceID, CategoryID: categoryID, Data: encoded})
+}
@@ -0,0 +1,45 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Publish sends an event to the clients of every server instance. It must be
+// called with the transaction of the change, so that the event is only
+// delivered when the change commits.
+//
+// On Postgres the event is sent with LISTEN/NOTIFY. Other databases have no
+// way to reach other instances, so the event is broadcast to the hub of this
+// instance, which only suits a single server. Run the transaction with
+// Transaction to hold the event back until the commit.
+func Publish(tx *gorm.DB, event Event, recordID uint, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	event.Data = encoded
+
+	if isPostgres(tx) {
+		return notify(tx, event, recordID)
+	}
+	publishLocal(tx, event)
+	return nil
+}
+
+// PublishPresence sends a presence update to every server instance. Presence
+// is not transactional, so it is sent right away.
+func PublishPresence(db *gorm.DB, update PresenceUpdate) error {
+	if isPostgres(db) {
+		return notifyPresence(db, update)
+	}
+	hub.ApplyPresence(update)
+	return nil
+}
+
+// isPostgres reports whether events go through Postgres LISTEN/NOTIFY
+func isPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
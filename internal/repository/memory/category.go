@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+)
+
+// CategoryRepository is the in-memory repository.CategoryRepository
+type CategoryRepository struct {
+	store *Store
+}
+
+var _ repository.CategoryRepository = (*CategoryRepository)(nil)
+
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+// Create Category
+func (r *CategoryRepository) CreateCategory(userID uint, req models.CreateCategoryRequest) (*models.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	color := req.Color
+	if color == "" {
+		color = "#3B82F6"
+	}
+
+	var workspaceID uint
+	if req.WorkspaceID != nil {
+		workspaceID = *req.WorkspaceID
+	} else {
+		id, err := s.personalWorkspaceID(userID)
+		if err != nil {
+			return nil, err
+		}
+		workspaceID = id
+	}
+
+	if !s.isMember(workspaceID, userID) {
+		return nil, services.ErrWorkspaceNotFound
+	}
+	if err := s.requireWrite(workspaceID, userID); err != nil {
+		return nil, err
+	}
+	if s.nameTaken(workspaceID, req.Name, 0) {
+		return nil, services.ErrCategoryNameTaken
+	}
+
+	now := time.Now()
+	category := &models.Category{
+		ID:          s.newID("categories"),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Name:        req.Name,
+		Color:       color,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.categories[category.ID] = category
+
+	result := *category
+	return &result, nil
+}
+
+// Get Category by ID
+func (r *CategoryRepository) GetCategoryByID(userID, id uint) (*models.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	category, err := s.memberCategory(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *category
+	return &result, nil
+}
+
+// Get All Categories
+func (r *CategoryRepository) GetAllCategories(userID, workspaceID uint) ([]models.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var categories []models.Category
+	for _, category := range s.categories {
+		if category.DeletedAt.Valid || !s.isMember(category.WorkspaceID, userID) {
+			continue
+		}
+		if workspaceID != 0 && category.WorkspaceID != workspaceID {
+			continue
+		}
+		categories = append(categories, *category)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+	return categories, nil
+}
+
+// Update Category
+func (r *CategoryRepository) UpdateCategory(userID, id uint, req models.UpdateCategoryRequest) (*models.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	category, err := s.memberCategory(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireWrite(category.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && s.nameTaken(category.WorkspaceID, *req.Name, category.ID) {
+		return nil, services.ErrCategoryNameTaken
+	}
+
+	if req.Name != nil {
+		category.Name = *req.Name
+	}
+	if req.Color != nil {
+		category.Color = *req.Color
+	}
+	category.UpdatedAt = time.Now()
+
+	result := *category
+	return &result, nil
+}
+
+// Delete Category moves the category to the trash, handling its todos with
+// the strategy like services.CategoryService does
+func (r *CategoryRepository) DeleteCategory(userID, id uint, params models.DeleteCategoryParams) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	strategy := params.Strategy
+	if strategy == "" {
+		strategy = models.CategoryDeleteRestrict
+	}
+	if strategy != models.CategoryDeleteRestrict && strategy != models.CategoryDeleteReassign && strategy != models.CategoryDeleteCascade {
+		return services.ErrInvalidDeleteStrategy
+	}
+
+	category, err := s.memberCategory(userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.requireWrite(category.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	var target *models.Category
+	if strategy == models.CategoryDeleteReassign {
+		if params.Target == 0 {
+			return services.ErrReassignTargetRequired
+		}
+		if params.Target == category.ID {
+			return services.ErrReassignTargetSame
+		}
+		found, ok := s.category(params.Target)
+		if !ok || found.WorkspaceID != category.WorkspaceID {
+			return services.ErrReassignTargetNotFound
+		}
+		target = found
+	}
+
+	now := time.Now()
+	for _, todo := range s.todos {
+		if todo.CategoryID != category.ID {
+			continue
+		}
+
+		switch strategy {
+		case models.CategoryDeleteRestrict:
+			if !todo.DeletedAt.Valid {
+				return services.ErrCategoryInUse
+			}
+
+		case models.CategoryDeleteReassign:
+			// Todos in the trash move as well
+			todo.CategoryID = target.ID
+			todo.UpdatedAt = now
+
+		case models.CategoryDeleteCascade:
+			if !todo.DeletedAt.Valid {
+				todo.DeletedAt = softDelete(now)
+			}
+		}
+	}
+
+	category.DeletedAt = softDelete(now)
+	return nil
+}
+
+// memberCategory returns a category of a workspace the user is a member of
+func (s *Store) memberCategory(userID, id uint) (*models.Category, error) {
+	category, ok := s.category(id)
+	if !ok || !s.isMember(category.WorkspaceID, userID) {
+		return nil, services.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// nameTaken reports whether another category of the workspace has the name.
+// Like the unique index, categories in the trash count as well.
+func (s *Store) nameTaken(workspaceID uint, name string, exceptID uint) bool {
+	for _, category := range s.categories {
+		if category.ID != exceptID && category.WorkspaceID == workspaceID && category.Name == name {
+			return true
+		}
+	}
+	return false
+}
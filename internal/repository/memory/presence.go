@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+)
+
+// PresenceRepository is the in-memory repository.PresenceRepository. Updates
+// go to the hub of this instance, like services.PresenceService does on
+// databases other than Postgres.
+type PresenceRepository struct {
+	store *Store
+}
+
+var _ repository.PresenceRepository = (*PresenceRepository)(nil)
+
+func NewPresenceRepository(store *Store) *PresenceRepository {
+	return &PresenceRepository{store: store}
+}
+
+// Update Presence of a connection on a todo
+func (r *PresenceRepository) UpdatePresence(userID, todoID uint, state string, presence realtime.PresenceUpdate) (*realtime.PresenceUpdate, error) {
+	if state != realtime.PresenceViewing && state != realtime.PresenceEditing {
+		return nil, services.InvalidField("invalid_presence_state", "state", "invalid presence state. Must be 'viewing', 'editing', or 'idle'")
+	}
+
+	todo, err := r.store.presenceTodo(userID, todoID, state == realtime.PresenceEditing)
+	if err != nil {
+		return nil, err
+	}
+
+	presence.TodoID = todo.ID
+	presence.WorkspaceID = todo.WorkspaceID
+	presence.CategoryID = todo.CategoryID
+	presence.State = state
+
+	return &presence, r.RefreshPresence(&presence)
+}
+
+// presenceTodo returns a copy of a todo the user may view, or edit when write
+// is set
+func (s *Store) presenceTodo(userID, todoID uint, write bool) (*models.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, err := s.memberTodo(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if write {
+		if err := s.requireWrite(todo.WorkspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := *todo
+	return &result, nil
+}
+
+// Refresh Presence before it expires
+func (r *PresenceRepository) RefreshPresence(presence *realtime.PresenceUpdate) error {
+	presence.ExpiresAt = time.Now().Add(realtime.PresenceTTL)
+	realtime.GetHub().ApplyPresence(*presence)
+	return nil
+}
+
+// Clear Presence when the user leaves the todo or disconnects
+func (r *PresenceRepository) ClearPresence(presence realtime.PresenceUpdate) error {
+	presence.State = realtime.PresenceIdle
+	realtime.GetHub().ApplyPresence(presence)
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package memory implements the repositories in memory, so that the HTTP
+// layer can be tested with httptest and no database. It keeps
+// the access rules and error messages of the GORM implementations. Features
+// that need SQL (search, the q filter language, cursor pagination, bulk
+// operations, tags, recurrence and reminders) return ErrUnsupported, and no
+// events are emitted. Presence goes straight to the hub of this instance.
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+)
+
+// ErrUnsupported is returned for features the in-memory repositories do not have
+var ErrUnsupported = errors.New("not supported by the in-memory repository")
+
+// Store holds the workspaces, categories and todos shared by the repositories
+type Store struct {
+	mu sync.Mutex
+
+	nextID     map[string]uint
+	users      map[uint]*models.User
+	owners     map[uint]uint
+	members    map[uint]map[uint]models.WorkspaceRole
+	categories map[uint]*models.Category
+	todos      map[uint]*models.Todo
+}
+
+func NewStore() *Store {
+	return &Store{
+		nextID:     make(map[string]uint),
+		users:      make(map[uint]*models.User),
+		owners:     make(map[uint]uint),
+		members:    make(map[uint]map[uint]models.WorkspaceRole),
+		categories: make(map[uint]*models.Category),
+		todos:      make(map[uint]*models.Todo),
+	}
+}
+
+// AddUser adds a user for the user repository
+func (s *Store) AddUser(id uint, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[id] = &models.User{ID: id, Name: name, Email: fmt.Sprintf("user%d@example.com", id)}
+}
+
+// AddWorkspace creates a workspace owned by the user and returns its ID. The
+// first workspace of a user is their personal workspace.
+func (s *Store) AddWorkspace(ownerID uint) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID("workspaces")
+	s.owners[id] = ownerID
+	s.members[id] = map[uint]models.WorkspaceRole{ownerID: models.WorkspaceRoleOwner}
+	return id
+}
+
+// AddMember adds the user to the workspace, or changes their role
+func (s *Store) AddMember(workspaceID, userID uint, role models.WorkspaceRole) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.members[workspaceID] == nil {
+		s.members[workspaceID] = make(map[uint]models.WorkspaceRole)
+	}
+	s.members[workspaceID][userID] = role
+}
+
+// newID returns the next ID of a table; IDs start at 1 like a sequence
+func (s *Store) newID(table string) uint {
+	s.nextID[table]++
+	return s.nextID[table]
+}
+
+func (s *Store) role(workspaceID, userID uint) models.WorkspaceRole {
+	return s.members[workspaceID][userID]
+}
+
+func (s *Store) isMember(workspaceID, userID uint) bool {
+	return s.role(workspaceID, userID) != ""
+}
+
+func (s *Store) requireWrite(workspaceID, userID uint) error {
+	if !s.role(workspaceID, userID).CanWrite() {
+		return services.ErrInsufficientPermissions
+	}
+	return nil
+}
+
+// personalWorkspaceID returns the first workspace owned by the user
+func (s *Store) personalWorkspaceID(userID uint) (uint, error) {
+	var id uint
+	for workspaceID, ownerID := range s.owners {
+		if ownerID == userID && (id == 0 || workspaceID < id) {
+			id = workspaceID
+		}
+	}
+	if id == 0 {
+		return 0, services.ErrWorkspaceNotFound
+	}
+	return id, nil
+}
+
+// category returns a category that is not in the trash
+func (s *Store) category(id uint) (*models.Category, bool) {
+	category, ok := s.categories[id]
+	if !ok || category.DeletedAt.Valid {
+		return nil, false
+	}
+	return category, true
+}
+
+// todo returns a todo that is not in the trash
+func (s *Store) todo(id uint) (*models.Todo, bool) {
+	todo, ok := s.todos[id]
+	if !ok || todo.DeletedAt.Valid {
+		return nil, false
+	}
+	return todo, true
+}
+
+// copyTodo returns a copy of the todo with its category loaded, so callers
+// cannot change the store
+func (s *Store) copyTodo(todo *models.Todo) models.Todo {
+	result := *todo
+	if category, ok := s.category(todo.CategoryID); ok {
+		c := *category
+		result.Category = &c
+	}
+	return result
+}
+
+func softDelete(now time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: now, Valid: true}
+}
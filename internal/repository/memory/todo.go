@@ -0,0 +1,363 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+)
+
+// TodoRepository is the in-memory repository.TodoRepository
+type TodoRepository struct {
+	store *Store
+}
+
+var _ repository.TodoRepository = (*TodoRepository)(nil)
+
+func NewTodoRepository(store *Store) *TodoRepository {
+	return &TodoRepository{store: store}
+}
+
+// Create Todo
+func (r *TodoRepository) CreateTodo(userID uint, req models.CreateTodoRequest) (*models.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(req.TagIDs) > 0 || req.Recurrence != nil || len(req.ReminderOffsets) > 0 {
+		return nil, ErrUnsupported
+	}
+
+	category, err := s.memberCategory(userID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireWrite(category.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if !models.ValidatePriority(req.Priority) {
+		return nil, services.ErrInvalidPriority
+	}
+
+	now := time.Now()
+	todo := &models.Todo{
+		ID:           s.newID("todos"),
+		WorkspaceID:  category.WorkspaceID,
+		UserID:       userID,
+		Title:        req.Title,
+		Description:  req.Description,
+		CategoryID:   category.ID,
+		Priority:     req.Priority,
+		DueDate:      req.DueDate,
+		AutoComplete: req.AutoComplete,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	s.todos[todo.ID] = todo
+
+	result := s.copyTodo(todo)
+	return &result, nil
+}
+
+// Get Todo by ID
+func (r *TodoRepository) GetTodoByID(userID, id uint) (*models.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, err := s.memberTodo(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.copyTodo(todo)
+	return &result, nil
+}
+
+// Get Todos with offset pagination, filters and sorting
+func (r *TodoRepository) GetTodos(userID uint, params models.PaginationParams) ([]models.Todo, *models.Pagination, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if params.Search != "" || params.Query != "" || len(params.Tags) > 0 || params.Pagination == "cursor" || params.Cursor != "" {
+		return nil, nil, ErrUnsupported
+	}
+
+	sorts, err := services.ParseTodoSort(params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	var todos []models.Todo
+	for _, todo := range s.todos {
+		if todo.DeletedAt.Valid || !s.isMember(todo.WorkspaceID, userID) {
+			continue
+		}
+		if params.WorkspaceID != 0 && todo.WorkspaceID != params.WorkspaceID {
+			continue
+		}
+		if !matchesFilters(todo, params, now) {
+			continue
+		}
+		todos = append(todos, s.copyTodo(todo))
+	}
+
+	sort.SliceStable(todos, func(i, j int) bool {
+		for _, field := range sorts {
+			if c := compareTodos(&todos[i], &todos[j], field.Field); c != 0 {
+				// Todos without a due date come last in both directions
+				if field.Field == "due_date" && (todos[i].DueDate == nil) != (todos[j].DueDate == nil) {
+					return todos[j].DueDate == nil
+				}
+				if field.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	limit := params.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+
+	total := len(todos)
+	offset := (page - 1) * limit
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	totalPages := total / limit
+	if total%limit != 0 {
+		totalPages++
+	}
+
+	pagination := &models.Pagination{
+		CurrentPage: page,
+		PerPage:     limit,
+		Total:       int64(total),
+		TotalPages:  totalPages,
+	}
+
+	return todos[offset:end], pagination, nil
+}
+
+// Update Todo
+func (r *TodoRepository) UpdateTodo(userID, id uint, req models.UpdateTodoRequest) (*models.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.TagIDs != nil || req.Recurrence != nil || req.ReminderOffsets != nil {
+		return nil, ErrUnsupported
+	}
+
+	todo, err := s.memberTodo(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireWrite(todo.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	// Validate everything before changing the stored todo
+	updated := *todo
+	if req.Title != nil {
+		updated.Title = *req.Title
+	}
+	if req.Description != nil {
+		updated.Description = *req.Description
+	}
+	if req.Completed != nil {
+		updated.Completed = *req.Completed
+	}
+	if req.CategoryID != nil {
+		category, ok := s.category(*req.CategoryID)
+		if !ok || category.WorkspaceID != todo.WorkspaceID {
+			return nil, services.ErrTodoCategoryNotFound
+		}
+		updated.CategoryID = category.ID
+	}
+	if req.Priority != nil {
+		if !models.ValidatePriority(*req.Priority) {
+			return nil, services.ErrInvalidPriority
+		}
+		updated.Priority = *req.Priority
+	}
+	if req.DueDate != nil {
+		updated.DueDate = req.DueDate
+	}
+	if req.AutoComplete != nil {
+		updated.AutoComplete = *req.AutoComplete
+	}
+	updated.UpdatedAt = time.Now()
+	*todo = updated
+
+	result := s.copyTodo(todo)
+	return &result, nil
+}
+
+// Delete Todo moves the todo to the trash
+func (r *TodoRepository) DeleteTodo(userID, id uint) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, err := s.memberTodo(userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.requireWrite(todo.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	todo.DeletedAt = softDelete(time.Now())
+	return nil
+}
+
+// Toggle Todo Complete
+func (r *TodoRepository) ToggleComplete(userID, id uint) (*models.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, err := s.memberTodo(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireWrite(todo.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	todo.Completed = !todo.Completed
+	todo.UpdatedAt = time.Now()
+
+	result := s.copyTodo(todo)
+	return &result, nil
+}
+
+// Get Occurrences fails for every todo, since todos in memory cannot recur
+func (r *TodoRepository) GetOccurrences(userID, id uint, count int) ([]time.Time, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.memberTodo(userID, id); err != nil {
+		return nil, err
+	}
+
+	return nil, services.ErrTodoNotRecurring
+}
+
+// Bulk Update is not supported in memory
+func (r *TodoRepository) BulkUpdate(userID uint, req models.BulkTodoRequest) ([]models.BulkTodoResult, bool, error) {
+	return nil, false, ErrUnsupported
+}
+
+// memberTodo returns a todo of a workspace the user is a member of
+func (s *Store) memberTodo(userID, id uint) (*models.Todo, error) {
+	todo, ok := s.todo(id)
+	if !ok || !s.isMember(todo.WorkspaceID, userID) {
+		return nil, services.ErrTodoNotFound
+	}
+	return todo, nil
+}
+
+// matchesFilters applies the filters of services.applyTodoFilters. Lower
+// bounds are inclusive, upper bounds are exclusive.
+func matchesFilters(todo *models.Todo, params models.PaginationParams, now time.Time) bool {
+	if len(params.CategoryIDs) > 0 && !slices.Contains(params.CategoryIDs, todo.CategoryID) {
+		return false
+	}
+	if len(params.Priorities) > 0 && !slices.Contains(params.Priorities, todo.Priority) {
+		return false
+	}
+	if params.Completed != nil && todo.Completed != *params.Completed {
+		return false
+	}
+
+	if params.DueAfter != nil && (todo.DueDate == nil || todo.DueDate.Before(*params.DueAfter)) {
+		return false
+	}
+	if params.DueBefore != nil && (todo.DueDate == nil || !todo.DueDate.Before(*params.DueBefore)) {
+		return false
+	}
+	if params.CreatedAfter != nil && todo.CreatedAt.Before(*params.CreatedAfter) {
+		return false
+	}
+	if params.CreatedBefore != nil && !todo.CreatedAt.Before(*params.CreatedBefore) {
+		return false
+	}
+
+	if params.Overdue && (todo.Completed || todo.DueDate == nil || !todo.DueDate.Before(now)) {
+		return false
+	}
+
+	return true
+}
+
+// compareTodos compares a sort field of two todos like the ORDER BY
+// expressions of services.ParseTodoSort
+func compareTodos(a, b *models.Todo, field string) int {
+	switch field {
+	case "id":
+		return cmp.Compare(a.ID, b.ID)
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "priority":
+		return priorityRank(a.Priority) - priorityRank(b.Priority)
+	case "completed":
+		return boolRank(a.Completed) - boolRank(b.Completed)
+	case "due_date":
+		if a.DueDate == nil || b.DueDate == nil {
+			return boolRank(a.DueDate == nil) - boolRank(b.DueDate == nil)
+		}
+		return a.DueDate.Compare(*b.DueDate)
+	case "created_at":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+	return 0
+}
+
+func priorityRank(p models.Priority) int {
+	switch p {
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityMedium:
+		return 2
+	case models.PriorityLow:
+		return 1
+	}
+	return 0
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
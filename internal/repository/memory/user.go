@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+)
+
+// UserRepository is the in-memory repository.UserRepository
+type UserRepository struct {
+	store *Store
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// Get User by ID
+func (r *UserRepository) GetUserByID(id uint) (*models.User, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, services.NotFound("user_not_found", "user not found")
+	}
+
+	result := *user
+	return &result, nil
+}
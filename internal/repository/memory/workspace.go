@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+)
+
+// WorkspaceRepository is the in-memory repository.WorkspaceRepository
+type WorkspaceRepository struct {
+	store *Store
+}
+
+var _ repository.WorkspaceRepository = (*WorkspaceRepository)(nil)
+
+func NewWorkspaceRepository(store *Store) *WorkspaceRepository {
+	return &WorkspaceRepository{store: store}
+}
+
+// Get Member Workspace IDs
+func (r *WorkspaceRepository) GetMemberWorkspaceIDs(userID uint) ([]uint, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []uint
+	for workspaceID := range s.members {
+		if s.isMember(workspaceID, userID) {
+			ids = append(ids, workspaceID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
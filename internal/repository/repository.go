@@ -0,0 +1,54 @@
+// Package repository defines the storage used by the todo, category and
+// collaboration handlers. The GORM implementations are the matching services
+// (services.TodoService, services.CategoryService, ...); package memory keeps
+// everything in memory for tests that run without a database.
+package repository
+
+import (
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+)
+
+// TodoRepository stores the todos of the workspaces a user is a member of
+type TodoRepository interface {
+	CreateTodo(userID uint, req models.CreateTodoRequest) (*models.Todo, error)
+	GetTodoByID(userID, id uint) (*models.Todo, error)
+	GetTodos(userID uint, params models.PaginationParams) ([]models.Todo, *models.Pagination, error)
+	UpdateTodo(userID, id uint, req models.UpdateTodoRequest) (*models.Todo, error)
+	DeleteTodo(userID, id uint) error
+	ToggleComplete(userID, id uint) (*models.Todo, error)
+	GetOccurrences(userID, id uint, count int) ([]time.Time, error)
+	BulkUpdate(userID uint, req models.BulkTodoRequest) ([]models.BulkTodoResult, bool, error)
+}
+
+// CategoryRepository stores the categories of the workspaces a user is a
+// member of
+type CategoryRepository interface {
+	CreateCategory(userID uint, req models.CreateCategoryRequest) (*models.Category, error)
+	GetCategoryByID(userID, id uint) (*models.Category, error)
+	GetAllCategories(userID, workspaceID uint) ([]models.Category, error)
+	UpdateCategory(userID, id uint, req models.UpdateCategoryRequest) (*models.Category, error)
+	DeleteCategory(userID, id uint, params models.DeleteCategoryParams) error
+}
+
+// UserRepository looks up users; the GORM implementation is
+// services.AuthService
+type UserRepository interface {
+	GetUserByID(id uint) (*models.User, error)
+}
+
+// WorkspaceRepository lists workspace memberships; the GORM implementation is
+// services.WorkspaceService
+type WorkspaceRepository interface {
+	GetMemberWorkspaceIDs(userID uint) ([]uint, error)
+}
+
+// PresenceRepository checks access to a todo and publishes who is viewing or
+// editing it; the GORM implementation is services.PresenceService
+type PresenceRepository interface {
+	UpdatePresence(userID, todoID uint, state string, presence realtime.PresenceUpdate) (*realtime.PresenceUpdate, error)
+	RefreshPresence(presence *realtime.PresenceUpdate) error
+	ClearPresence(presence realtime.PresenceUpdate) error
+}
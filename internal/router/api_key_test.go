@@ -0,0 +1,116 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/jayasaleh/todo-list/be/internal/config"
+	"github.com/jayasaleh/todo-list/be/internal/database"
+	"github.com/jayasaleh/todo-list/be/internal/migrator"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/migrations"
+)
+
+// newTestAPIKeys returns the API key service on a new SQLite database and a
+// user to create keys for. API keys are only looked up by hash in SQL, so
+// they have no in-memory implementation.
+func newTestAPIKeys(t *testing.T) (*services.APIKeyService, *gorm.DB, uint) {
+	t.Helper()
+
+	cfg := &config.Config{DBDriver: config.DriverSQLite, DBPath: filepath.Join(t.TempDir(), "test.db"), JWTSecret: "test-secret"}
+	db, err := database.Connect(cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	source, err := migrations.ForDriver(cfg.DBDriver)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	mig, err := migrator.New(db, source)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err := mig.Up(context.Background()); err != nil {
+		t.Fatalf("failed to migrate the test database: %v", err)
+	}
+
+	user, err := services.NewAuthService(db, cfg).Register(models.RegisterRequest{Name: "Test", Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return services.NewAPIKeyService(db), db, user.ID
+}
+
+func TestAPIKeyAccess(t *testing.T) {
+	apiKeys, db, userID := newTestAPIKeys(t)
+	api := newTestAPIWithKeys(t, apiKeys)
+	api.store.AddWorkspace(userID)
+
+	newKey := func(scopes ...string) (*models.APIKey, string) {
+		key, rawKey, err := apiKeys.CreateAPIKey(userID, models.CreateAPIKeyRequest{Name: "Script", Scopes: scopes})
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+		return key, rawKey
+	}
+
+	var category models.CategoryResponse
+	expect(t, api.do(t, userID, http.MethodPost, "/api/categories", models.CreateCategoryRequest{Name: "Home"}), http.StatusCreated, "", &category)
+	newTodo := models.CreateTodoRequest{Title: "Backup", CategoryID: category.ID, Priority: models.PriorityLow}
+
+	t.Run("scopes", func(t *testing.T) {
+		_, readKey := newKey(models.ScopeTodosRead)
+		expect(t, api.doWithToken(t, readKey, http.MethodGet, "/api/todos", nil), http.StatusOK, "", nil)
+		expect(t, api.doWithToken(t, readKey, http.MethodPost, "/api/todos", newTodo), http.StatusForbidden, "forbidden", nil)
+		expect(t, api.doWithToken(t, readKey, http.MethodGet, "/api/categories", nil), http.StatusForbidden, "forbidden", nil)
+
+		_, writeKey := newKey(models.ScopeTodosWrite)
+		expect(t, api.doWithToken(t, writeKey, http.MethodPost, "/api/todos", newTodo), http.StatusCreated, "", nil)
+		expect(t, api.doWithToken(t, writeKey, http.MethodGet, "/api/todos", nil), http.StatusOK, "", nil)
+	})
+
+	t.Run("revoked and expired", func(t *testing.T) {
+		revoked, revokedKey := newKey(models.ScopeTodosRead)
+		expect(t, api.doWithToken(t, revokedKey, http.MethodGet, "/api/todos", nil), http.StatusOK, "", nil)
+		if _, err := apiKeys.RevokeAPIKey(userID, revoked.ID); err != nil {
+			t.Fatalf("RevokeAPIKey() error = %v", err)
+		}
+		expect(t, api.doWithToken(t, revokedKey, http.MethodGet, "/api/todos", nil), http.StatusUnauthorized, "unauthorized", nil)
+
+		expired, expiredKey := newKey(models.ScopeTodosRead)
+		if err := db.Model(expired).Update("expires_at", time.Now().Add(-time.Second)).Error; err != nil {
+			t.Fatalf("failed to expire api key: %v", err)
+		}
+		expect(t, api.doWithToken(t, expiredKey, http.MethodGet, "/api/todos", nil), http.StatusUnauthorized, "unauthorized", nil)
+
+		expect(t, api.doWithToken(t, services.APIKeyPrefix+"unknown", http.MethodGet, "/api/todos", nil), http.StatusUnauthorized, "unauthorized", nil)
+	})
+
+	t.Run("account endpoints need a session", func(t *testing.T) {
+		_, key := newKey(models.Scopes...)
+		expect(t, api.doWithToken(t, key, http.MethodGet, "/api/api-keys", nil), http.StatusForbidden, "forbidden", nil)
+		expect(t, api.doWithToken(t, key, http.MethodPost, "/api/api-keys", models.CreateAPIKeyRequest{Name: "Escalated", Scopes: models.Scopes}), http.StatusForbidden, "forbidden", nil)
+
+		var keys []models.APIKeyResponse
+		expect(t, api.do(t, userID, http.MethodGet, "/api/api-keys", nil), http.StatusOK, "", &keys)
+		for _, k := range keys {
+			if k.Name == "Escalated" {
+				t.Errorf("api key created with an api key: %+v", k)
+			}
+		}
+	})
+}
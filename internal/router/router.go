@@ -2,42 +2,218 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/config"
 	"github.com/jayasaleh/todo-list/be/internal/handlers"
+	"github.com/jayasaleh/todo-list/be/internal/httperr"
 	"github.com/jayasaleh/todo-list/be/internal/middleware"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+	"github.com/jayasaleh/todo-list/be/internal/services"
 	"github.com/jayasaleh/todo-list/be/pkg/utils"
+	"gorm.io/gorm"
 )
 
-func SetupRouter() *gin.Engine {
-	router := gin.Default()
+// Services are the dependencies of the HTTP handlers
+type Services struct {
+	Auth       *services.AuthService
+	APIKeys    *services.APIKeyService
+	Workspaces *services.WorkspaceService
+	Todos      repository.TodoRepository
+	TodoItems  *services.TodoItemService
+	Categories repository.CategoryRepository
+	Tags       *services.TagService
+	SavedViews *services.SavedViewService
+	Trash      *services.TrashService
+	Webhooks   *services.WebhookService
+	Presence   *services.PresenceService
+}
+
+// NewServices creates the services backed by the database
+func NewServices(cfg *config.Config, db *gorm.DB) Services {
+	todos := services.NewTodoService(db)
+
+	return Services{
+		Auth:       services.NewAuthService(db, cfg),
+		APIKeys:    services.NewAPIKeyService(db),
+		Workspaces: services.NewWorkspaceService(db),
+		Todos:      todos,
+		TodoItems:  services.NewTodoItemService(db),
+		Categories: services.NewCategoryService(db),
+		Tags:       services.NewTagService(db),
+		SavedViews: services.NewSavedViewService(db, todos),
+		Trash:      services.NewTrashService(db, cfg.TrashRetention),
+		Webhooks:   services.NewWebhookService(db),
+		Presence:   services.NewPresenceService(db),
+	}
+}
+
+// SetupRouter creates the routes on top of the given services
+func SetupRouter(cfg *config.Config, svc Services) *gin.Engine {
+	httperr.UseFieldTagNames()
 
-	router.Use(middleware.CORSMiddleware())
+	// Like gin.Default, with a logger that redacts tokens in query strings
+	router := gin.New()
+	router.Use(middleware.Logger(), gin.Recovery())
+
+	router.Use(middleware.CORSMiddleware(cfg.CORSAllowedOrigins))
 
 	router.GET("/health", func(c *gin.Context) {
 		utils.OK(c, "Todo List API is running", nil)
 	})
 
-	todoHandler := handlers.NewTodoHandler()
-	categoryHandler := handlers.NewCategoryHandler()
+	authHandler := handlers.NewAuthHandler(svc.Auth)
+	apiKeyHandler := handlers.NewAPIKeyHandler(svc.APIKeys)
+	workspaceHandler := handlers.NewWorkspaceHandler(svc.Workspaces)
+	todoHandler := handlers.NewTodoHandler(svc.Todos)
+	todoItemHandler := handlers.NewTodoItemHandler(svc.TodoItems)
+	categoryHandler := handlers.NewCategoryHandler(svc.Categories)
+	tagHandler := handlers.NewTagHandler(svc.Tags)
+	savedViewHandler := handlers.NewSavedViewHandler(svc.SavedViews)
+	trashHandler := handlers.NewTrashHandler(svc.Trash)
+	webhookHandler := handlers.NewWebhookHandler(svc.Webhooks)
+	eventHandler := handlers.NewEventHandler(svc.Workspaces)
+	collabHandler := handlers.NewCollabHandler(svc.Auth, svc.Todos, svc.Workspaces, svc.Presence, cfg.CORSAllowedOrigins)
 
 	api := router.Group("/api")
 	{
-		todos := api.Group("/todos")
+		auth := api.Group("/auth")
+		{
+			auth.POST("/register", authHandler.Register)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+		}
+
+		// Real-time updates (Server-Sent Events). The token may also be passed
+		// as ?access_token=, since EventSource cannot send headers.
+		api.GET("/events",
+			middleware.QueryToken(),
+			middleware.AuthMiddleware(svc.Auth, svc.APIKeys),
+			middleware.RequireScope("todos"),
+			eventHandler.Stream,
+		)
+
+		// Collaboration channel (WebSocket). Browsers cannot send headers on
+		// WebSocket connections either.
+		api.GET("/ws",
+			middleware.QueryToken(),
+			middleware.AuthMiddleware(svc.Auth, svc.APIKeys),
+			middleware.RequireScope("todos"),
+			collabHandler.Connect,
+		)
+	}
+
+	// Everything below requires an authenticated user
+	protected := api.Group("")
+	protected.Use(middleware.AuthMiddleware(svc.Auth, svc.APIKeys))
+	{
+		// Account management is only available to interactive sessions
+		account := protected.Group("")
+		account.Use(middleware.RequireSession())
+		{
+			account.POST("/auth/logout", authHandler.Logout)
+			account.POST("/auth/logout-all", authHandler.LogoutAll)
+			account.GET("/auth/me", authHandler.Me)
+
+			apiKeys := account.Group("/api-keys")
+			{
+				apiKeys.GET("", apiKeyHandler.GetAPIKeys)
+				apiKeys.POST("", apiKeyHandler.CreateAPIKey)
+				apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+			}
+
+			workspaces := account.Group("/workspaces")
+			{
+				workspaces.GET("", workspaceHandler.GetWorkspaces)
+				workspaces.GET("/:id", workspaceHandler.GetWorkspace)
+				workspaces.POST("", workspaceHandler.CreateWorkspace)
+				workspaces.PUT("/:id", workspaceHandler.UpdateWorkspace)
+				workspaces.DELETE("/:id", workspaceHandler.DeleteWorkspace)
+				workspaces.GET("/:id/members", workspaceHandler.GetMembers)
+				workspaces.PUT("/:id/members/:userId", workspaceHandler.UpdateMember)
+				workspaces.DELETE("/:id/members/:userId", workspaceHandler.RemoveMember)
+				workspaces.GET("/:id/invitations", workspaceHandler.GetWorkspaceInvitations)
+				workspaces.POST("/:id/invitations", workspaceHandler.CreateInvitation)
+				workspaces.DELETE("/:id/invitations/:invitationId", workspaceHandler.CancelInvitation)
+			}
+
+			webhooks := account.Group("/webhooks")
+			{
+				webhooks.GET("", webhookHandler.GetWebhooks)
+				webhooks.GET("/:id", webhookHandler.GetWebhook)
+				webhooks.POST("", webhookHandler.CreateWebhook)
+				webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+				webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+				webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+			}
+
+			invitations := account.Group("/invitations")
+			{
+				invitations.GET("", workspaceHandler.GetMyInvitations)
+				invitations.POST("/:id/accept", workspaceHandler.AcceptInvitation)
+				invitations.POST("/:id/decline", workspaceHandler.DeclineInvitation)
+			}
+		}
+
+		todos := protected.Group("/todos")
+		todos.Use(middleware.RequireScope("todos"))
 		{
 			todos.GET("", todoHandler.GetTodos)
 			todos.GET("/:id", todoHandler.GetTodo)
 			todos.POST("", todoHandler.CreateTodo)
+			todos.POST("/bulk", todoHandler.BulkUpdate)
 			todos.PUT("/:id", todoHandler.UpdateTodo)
 			todos.DELETE("/:id", todoHandler.DeleteTodo)
+			todos.POST("/:id/restore", trashHandler.RestoreTodo)
+			todos.DELETE("/:id/permanent", trashHandler.PurgeTodo)
 			todos.PATCH("/:id/complete", todoHandler.ToggleComplete)
+			todos.GET("/:id/occurrences", todoHandler.GetOccurrences)
+
+			todos.GET("/:id/items", todoItemHandler.GetItems)
+			todos.POST("/:id/items", todoItemHandler.CreateItem)
+			todos.PUT("/:id/items/reorder", todoItemHandler.ReorderItems)
+			todos.PUT("/:id/items/:itemId", todoItemHandler.UpdateItem)
+			todos.DELETE("/:id/items/:itemId", todoItemHandler.DeleteItem)
+			todos.PATCH("/:id/items/:itemId/complete", todoItemHandler.ToggleItem)
 		}
 
-		categories := api.Group("/categories")
+		categories := protected.Group("/categories")
+		categories.Use(middleware.RequireScope("categories"))
 		{
 			categories.GET("", categoryHandler.GetCategories)
 			categories.GET("/:id", categoryHandler.GetCategory)
 			categories.POST("", categoryHandler.CreateCategory)
 			categories.PUT("/:id", categoryHandler.UpdateCategory)
 			categories.DELETE("/:id", categoryHandler.DeleteCategory)
+			categories.POST("/:id/restore", trashHandler.RestoreCategory)
+			categories.DELETE("/:id/permanent", trashHandler.PurgeCategory)
+		}
+
+		tags := protected.Group("/tags")
+		tags.Use(middleware.RequireScope("tags"))
+		{
+			tags.GET("", tagHandler.GetTags)
+			tags.GET("/:id", tagHandler.GetTag)
+			tags.POST("", tagHandler.CreateTag)
+			tags.PUT("/:id", tagHandler.UpdateTag)
+			tags.DELETE("/:id", tagHandler.DeleteTag)
+		}
+
+		// The trash lists deleted todos and categories
+		protected.GET("/trash",
+			middleware.RequireScope("todos"),
+			middleware.RequireScope("categories"),
+			trashHandler.GetTrash,
+		)
+
+		// Saved views are todo list queries, so they share the todos scope
+		views := protected.Group("/views")
+		views.Use(middleware.RequireScope("todos"))
+		{
+			views.GET("", savedViewHandler.GetViews)
+			views.GET("/:id", savedViewHandler.GetView)
+			views.POST("", savedViewHandler.CreateView)
+			views.PUT("/:id", savedViewHandler.UpdateView)
+			views.DELETE("/:id", savedViewHandler.DeleteView)
+			views.GET("/:id/todos", savedViewHandler.GetViewTodos)
 		}
 	}
 
@@ -0,0 +1,306 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jayasaleh/todo-list/be/internal/config"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/repository/memory"
+	"github.com/jayasaleh/todo-list/be/internal/services"
+	"github.com/jayasaleh/todo-list/be/pkg/utils"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	os.Exit(m.Run())
+}
+
+// testAPI is a router on the in-memory store. Only the services the todo and
+// category routes need are set; none of them uses a database.
+type testAPI struct {
+	router *gin.Engine
+	store  *memory.Store
+	auth   *services.AuthService
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	return newTestAPIWithKeys(t, nil)
+}
+
+// newTestAPIWithKeys also accepts the API keys of apiKeys, which needs a
+// database (see newTestAPIKeys)
+func newTestAPIWithKeys(t *testing.T, apiKeys *services.APIKeyService) *testAPI {
+	t.Helper()
+
+	cfg := &config.Config{
+		JWTSecret:          "test-secret",
+		JWTIssuer:          "todo-list-test",
+		AccessTokenTTL:     time.Hour,
+		CORSAllowedOrigins: []string{"*"},
+	}
+	store := memory.NewStore()
+	todos := memory.NewTodoRepository(store)
+	auth := services.NewAuthService(nil, cfg)
+
+	svc := Services{
+		Auth:       auth,
+		APIKeys:    apiKeys,
+		Todos:      todos,
+		Categories: memory.NewCategoryRepository(store),
+		SavedViews: services.NewSavedViewService(nil, todos),
+	}
+
+	return &testAPI{router: SetupRouter(cfg, svc), store: store, auth: auth}
+}
+
+// testResponse is the response envelope of every endpoint
+type testResponse struct {
+	Code      int                `json:"code"`
+	Status    string             `json:"status"`
+	Message   string             `json:"message"`
+	ErrorCode string             `json:"error_code"`
+	Errors    []utils.FieldError `json:"errors"`
+	Data      json.RawMessage    `json:"data"`
+}
+
+// do sends a request as the user; userID 0 sends no token
+func (a *testAPI) do(t *testing.T, userID uint, method, path string, body interface{}) testResponse {
+	t.Helper()
+
+	var token string
+	if userID != 0 {
+		var err error
+		if token, _, err = a.auth.NewAccessToken(userID); err != nil {
+			t.Fatalf("NewAccessToken() error = %v", err)
+		}
+	}
+	return a.doWithToken(t, token, method, path, body)
+}
+
+// doWithToken sends a request with the bearer token, e.g. an API key
+func (a *testAPI) doWithToken(t *testing.T, token, method, path string, body interface{}) testResponse {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	a.router.ServeHTTP(rec, req)
+
+	var resp testResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s: invalid response %q: %v", method, path, rec.Body.String(), err)
+	}
+	if resp.Code != rec.Code {
+		t.Errorf("%s %s: body code = %d, status = %d", method, path, resp.Code, rec.Code)
+	}
+	return resp
+}
+
+// expect checks the status and error code of a response and decodes its data
+func expect(t *testing.T, resp testResponse, code int, errorCode string, data interface{}) {
+	t.Helper()
+
+	if resp.Code != code || resp.ErrorCode != errorCode {
+		t.Fatalf("response = %d %q (%s), want %d %q", resp.Code, resp.ErrorCode, resp.Message, code, errorCode)
+	}
+	if data != nil {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			t.Fatalf("invalid data %s: %v", resp.Data, err)
+		}
+	}
+}
+
+func TestCategoryHandlers(t *testing.T) {
+	api := newTestAPI(t)
+	api.store.AddWorkspace(1)
+	api.store.AddWorkspace(2)
+
+	var work models.CategoryResponse
+	expect(t, api.do(t, 1, http.MethodPost, "/api/categories", map[string]string{"name": "Work"}), http.StatusCreated, "", &work)
+	if work.Name != "Work" || work.Color != "#3B82F6" {
+		t.Errorf("created category = %+v", work)
+	}
+	path := fmt.Sprintf("/api/categories/%d", work.ID)
+
+	t.Run("create", func(t *testing.T) {
+		resp := api.do(t, 1, http.MethodPost, "/api/categories", map[string]string{"name": "Work"})
+		expect(t, resp, http.StatusConflict, "category_name_taken", nil)
+
+		resp = api.do(t, 1, http.MethodPost, "/api/categories", map[string]string{"color": "#000000"})
+		expect(t, resp, http.StatusBadRequest, "validation_failed", nil)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var categories []models.CategoryResponse
+		expect(t, api.do(t, 1, http.MethodGet, "/api/categories", nil), http.StatusOK, "", &categories)
+		if len(categories) != 1 || categories[0].ID != work.ID {
+			t.Errorf("categories = %+v", categories)
+		}
+
+		expect(t, api.do(t, 1, http.MethodGet, "/api/categories?workspace_id=x", nil), http.StatusBadRequest, "bad_request", nil)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var category models.CategoryResponse
+		expect(t, api.do(t, 1, http.MethodGet, path, nil), http.StatusOK, "", &category)
+		if category.ID != work.ID {
+			t.Errorf("category = %+v", category)
+		}
+
+		expect(t, api.do(t, 1, http.MethodGet, "/api/categories/abc", nil), http.StatusBadRequest, "bad_request", nil)
+		expect(t, api.do(t, 1, http.MethodGet, "/api/categories/999", nil), http.StatusNotFound, "category_not_found", nil)
+		// Categories of other workspaces look like missing categories
+		expect(t, api.do(t, 2, http.MethodGet, path, nil), http.StatusNotFound, "category_not_found", nil)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var category models.CategoryResponse
+		resp := api.do(t, 1, http.MethodPut, path, map[string]string{"name": "Office", "color": "#FF0000"})
+		expect(t, resp, http.StatusOK, "", &category)
+		if category.Name != "Office" || category.Color != "#FF0000" {
+			t.Errorf("updated category = %+v", category)
+		}
+	})
+
+	t.Run("requires a token", func(t *testing.T) {
+		expect(t, api.do(t, 0, http.MethodGet, "/api/categories", nil), http.StatusUnauthorized, "unauthorized", nil)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		expect(t, api.do(t, 2, http.MethodDelete, path, nil), http.StatusNotFound, "category_not_found", nil)
+		expect(t, api.do(t, 1, http.MethodDelete, path, nil), http.StatusOK, "", nil)
+		expect(t, api.do(t, 1, http.MethodGet, path, nil), http.StatusNotFound, "category_not_found", nil)
+	})
+}
+
+func TestTodoHandlers(t *testing.T) {
+	api := newTestAPI(t)
+	api.store.AddWorkspace(1)
+	api.store.AddWorkspace(2)
+
+	var category models.CategoryResponse
+	expect(t, api.do(t, 1, http.MethodPost, "/api/categories", map[string]string{"name": "Home"}), http.StatusCreated, "", &category)
+
+	create := func(title string, priority models.Priority) models.TodoResponse {
+		t.Helper()
+		var todo models.TodoResponse
+		resp := api.do(t, 1, http.MethodPost, "/api/todos", models.CreateTodoRequest{Title: title, CategoryID: category.ID, Priority: priority})
+		expect(t, resp, http.StatusCreated, "", &todo)
+		return todo
+	}
+	milk := create("Buy milk", models.PriorityLow)
+	rent := create("Pay rent", models.PriorityHigh)
+	path := fmt.Sprintf("/api/todos/%d", milk.ID)
+
+	t.Run("create", func(t *testing.T) {
+		if milk.Title != "Buy milk" || milk.CategoryID != category.ID || milk.Completed {
+			t.Errorf("created todo = %+v", milk)
+		}
+
+		resp := api.do(t, 1, http.MethodPost, "/api/todos", map[string]interface{}{"category_id": category.ID, "priority": "low"})
+		expect(t, resp, http.StatusBadRequest, "validation_failed", nil)
+
+		resp = api.do(t, 1, http.MethodPost, "/api/todos", map[string]interface{}{"title": "x", "category_id": 999, "priority": "low"})
+		expect(t, resp, http.StatusNotFound, "category_not_found", nil)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var todos []models.TodoResponse
+		expect(t, api.do(t, 1, http.MethodGet, "/api/todos?sort=-priority", nil), http.StatusOK, "", &todos)
+		if len(todos) != 2 || todos[0].ID != rent.ID || todos[1].ID != milk.ID {
+			t.Errorf("todos = %+v", todos)
+		}
+
+		expect(t, api.do(t, 1, http.MethodGet, "/api/todos?priority=high", nil), http.StatusOK, "", &todos)
+		if len(todos) != 1 || todos[0].ID != rent.ID {
+			t.Errorf("high priority todos = %+v", todos)
+		}
+
+		expect(t, api.do(t, 2, http.MethodGet, "/api/todos", nil), http.StatusOK, "", &todos)
+		if len(todos) != 0 {
+			t.Errorf("todos of another user = %+v", todos)
+		}
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		tests := map[string]string{
+			"priority=urgent": "priority",
+			"category_id=0":   "category_id",
+			"tag_mode=none":   "tag_mode",
+			"pagination=page": "pagination",
+			"due_after=2030-01-02T00:00:00Z&due_before=2030-01-01T00:00:00Z":         "due_after",
+			"created_after=2030-01-02T00:00:00Z&created_before=2030-01-01T00:00:00Z": "created_after",
+		}
+		for query, field := range tests {
+			resp := api.do(t, 1, http.MethodGet, "/api/todos?"+query, nil)
+			expect(t, resp, http.StatusBadRequest, "invalid_filter", nil)
+			if len(resp.Errors) != 1 || resp.Errors[0].Field != field || resp.Errors[0].Code != "invalid_filter" {
+				t.Errorf("%s: errors = %+v, want the %s field", query, resp.Errors, field)
+			}
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var todo models.TodoResponse
+		expect(t, api.do(t, 1, http.MethodGet, path, nil), http.StatusOK, "", &todo)
+		if todo.ID != milk.ID {
+			t.Errorf("todo = %+v", todo)
+		}
+
+		expect(t, api.do(t, 1, http.MethodGet, "/api/todos/abc", nil), http.StatusBadRequest, "bad_request", nil)
+		expect(t, api.do(t, 2, http.MethodGet, path, nil), http.StatusNotFound, "todo_not_found", nil)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var todo models.TodoResponse
+		resp := api.do(t, 1, http.MethodPut, path, map[string]string{"title": "Buy oat milk"})
+		expect(t, resp, http.StatusOK, "", &todo)
+		if todo.Title != "Buy oat milk" || todo.Priority != models.PriorityLow {
+			t.Errorf("updated todo = %+v", todo)
+		}
+	})
+
+	t.Run("toggle complete", func(t *testing.T) {
+		var todo models.TodoResponse
+		expect(t, api.do(t, 1, http.MethodPatch, path+"/complete", nil), http.StatusOK, "", &todo)
+		if !todo.Completed {
+			t.Errorf("toggled todo = %+v", todo)
+		}
+	})
+
+	t.Run("viewer cannot write", func(t *testing.T) {
+		api.store.AddMember(milk.WorkspaceID, 3, models.WorkspaceRoleViewer)
+
+		expect(t, api.do(t, 3, http.MethodGet, path, nil), http.StatusOK, "", nil)
+		expect(t, api.do(t, 3, http.MethodDelete, path, nil), http.StatusForbidden, "insufficient_permissions", nil)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		expect(t, api.do(t, 1, http.MethodDelete, path, nil), http.StatusOK, "", nil)
+		expect(t, api.do(t, 1, http.MethodGet, path, nil), http.StatusNotFound, "todo_not_found", nil)
+	})
+}
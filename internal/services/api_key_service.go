@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+// APIKeyPrefix marks a bearer token as an API key rather than a JWT
+const APIKeyPrefix = "tdl_"
+
+// lastUsedResolution limits how often last_used_at is written for busy keys
+const lastUsedResolution = time.Minute
+
+type APIKeyService struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyService(db *gorm.DB) *APIKeyService {
+	return &APIKeyService{
+		db: db,
+	}
+}
+
+// IsAPIKey reports whether a bearer token looks like an API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// Create API Key. The raw key is returned once and never stored.
+func (s *APIKeyService) CreateAPIKey(userID uint, req models.CreateAPIKeyRequest) (*models.APIKey, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", InvalidField("name_required", "name", "name is required")
+	}
+
+	var scopes []string
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if !models.ValidateScope(scope) {
+			return nil, "", InvalidField("invalid_scope", "scopes", fmt.Sprintf("invalid scope %q. Must be one of: %s", scope, strings.Join(models.Scopes, ", ")))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", InvalidField("invalid_expires_at", "expires_at", "expires_at must be in the future")
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	rawKey := APIKeyPrefix + secret
+
+	key := models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    rawKey[:len(APIKeyPrefix)+8],
+		KeyHash:   hashToken(rawKey),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if err := s.db.Create(&key).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &key, rawKey, nil
+}
+
+// Get All API Keys of a user
+func (s *APIKeyService) GetAPIKeys(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+
+	if err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke API Key
+func (s *APIKeyService) RevokeAPIKey(userID, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+
+	if err := s.db.Where("user_id = ?", userID).First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NotFound("api_key_not_found", "api key not found")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		if err := s.db.Model(&key).Update("revoked_at", now).Error; err != nil {
+			return nil, fmt.Errorf("failed to revoke api key: %w", err)
+		}
+	}
+
+	return &key, nil
+}
+
+// Authenticate returns the active key matching the raw value and records its use
+func (s *APIKeyService) Authenticate(rawKey string) (*models.APIKey, error) {
+	var key models.APIKey
+
+	now := time.Now()
+	err := s.db.Where("key_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", hashToken(rawKey), now).
+		First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, Unauthorized("invalid_api_key", "invalid, expired or revoked api key")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	err = s.db.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", key.ID, now.Add(-lastUsedResolution)).
+		Update("last_used_at", now).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update api key usage: %w", err)
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+func newTestAPIKey(t *testing.T, userID uint, req models.CreateAPIKeyRequest) (*models.APIKey, string) {
+	t.Helper()
+
+	key, rawKey, err := NewAPIKeyService(testDB).CreateAPIKey(userID, req)
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	return key, rawKey
+}
+
+func getAPIKey(t *testing.T, id uint) models.APIKey {
+	t.Helper()
+
+	var key models.APIKey
+	if err := testDB.First(&key, id).Error; err != nil {
+		t.Fatalf("failed to get api key: %v", err)
+	}
+	return key
+}
+
+func TestAPIKeyCreateOnEngine(t *testing.T) {
+	s := NewAPIKeyService(testDB)
+	userID := newTestUser(t)
+
+	key, rawKey := newTestAPIKey(t, userID, models.CreateAPIKeyRequest{
+		Name:   " Backup script ",
+		Scopes: []string{models.ScopeTodosRead, models.ScopeTagsWrite, models.ScopeTodosRead},
+	})
+	if !IsAPIKey(rawKey) || !strings.HasPrefix(rawKey, key.Prefix) || len(key.Prefix) != len(APIKeyPrefix)+8 {
+		t.Errorf("key %q with prefix %q", rawKey, key.Prefix)
+	}
+	if key.Name != "Backup script" || key.Scopes != "todos:read,tags:write" {
+		t.Errorf("key = %+v, want a trimmed name and unique scopes", key)
+	}
+
+	// Only the hash is stored
+	stored := getAPIKey(t, key.ID)
+	if stored.KeyHash != hashToken(rawKey) || strings.Contains(stored.KeyHash, rawKey[len(APIKeyPrefix):]) {
+		t.Errorf("stored hash = %q, want the hash of the key", stored.KeyHash)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name  string
+		req   models.CreateAPIKeyRequest
+		field string
+	}{
+		{"no name", models.CreateAPIKeyRequest{Name: " "}, "name"},
+		{"invalid scope", models.CreateAPIKeyRequest{Name: "Script", Scopes: []string{"todos:admin"}}, "scopes"},
+		{"expired", models.CreateAPIKeyRequest{Name: "Script", ExpiresAt: &past}, "expires_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.CreateAPIKey(userID, tt.req)
+			var serr *Error
+			if !errors.As(err, &serr) || len(serr.Fields) != 1 || serr.Fields[0].Field != tt.field {
+				t.Errorf("CreateAPIKey() error = %v, want an invalid %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthenticateOnEngine(t *testing.T) {
+	s := NewAPIKeyService(testDB)
+	userID := newTestUser(t)
+	key, rawKey := newTestAPIKey(t, userID, models.CreateAPIKeyRequest{Name: "Script", Scopes: []string{models.ScopeTodosWrite}})
+
+	got, err := s.Authenticate(rawKey)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got.ID != key.ID || got.UserID != userID || len(got.ScopeList()) != 1 || got.ScopeList()[0] != models.ScopeTodosWrite {
+		t.Errorf("Authenticate() = %+v", got)
+	}
+
+	t.Run("last used", func(t *testing.T) {
+		first := getAPIKey(t, key.ID).LastUsedAt
+		if first == nil {
+			t.Fatal("last_used_at was not set")
+		}
+
+		// Busy keys are written at most once per lastUsedResolution
+		if _, err := s.Authenticate(rawKey); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+		if again := getAPIKey(t, key.ID).LastUsedAt; !again.Equal(*first) {
+			t.Errorf("last_used_at = %v, want it unchanged at %v", again, first)
+		}
+
+		stale := time.Now().Add(-lastUsedResolution - time.Second)
+		if err := testDB.Model(&models.APIKey{}).Where("id = ?", key.ID).Update("last_used_at", stale).Error; err != nil {
+			t.Fatalf("failed to age last_used_at: %v", err)
+		}
+		if _, err := s.Authenticate(rawKey); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+		if later := getAPIKey(t, key.ID).LastUsedAt; !later.After(stale.Add(time.Second)) {
+			t.Errorf("last_used_at = %v, want it updated", later)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		expired, expiredKey := newTestAPIKey(t, userID, models.CreateAPIKeyRequest{Name: "Expired"})
+		if err := testDB.Model(expired).Update("expires_at", time.Now().Add(-time.Second)).Error; err != nil {
+			t.Fatalf("failed to expire api key: %v", err)
+		}
+		revoked, revokedKey := newTestAPIKey(t, userID, models.CreateAPIKeyRequest{Name: "Revoked"})
+		if _, err := s.RevokeAPIKey(userID, revoked.ID); err != nil {
+			t.Fatalf("RevokeAPIKey() error = %v", err)
+		}
+
+		for name, rawKey := range map[string]string{
+			"expired": expiredKey,
+			"revoked": revokedKey,
+			"unknown": APIKeyPrefix + "unknown",
+			"altered": rawKey + "x",
+		} {
+			if _, err := s.Authenticate(rawKey); !isErrorCode(err, "invalid_api_key") {
+				t.Errorf("Authenticate() of the %s key error = %v, want invalid_api_key", name, err)
+			}
+		}
+	})
+}
+
+func TestAPIKeyRevokeOnEngine(t *testing.T) {
+	s := NewAPIKeyService(testDB)
+	userID := newTestUser(t)
+	key, _ := newTestAPIKey(t, userID, models.CreateAPIKeyRequest{Name: "Script"})
+
+	// Keys of other users are not found
+	if _, err := s.RevokeAPIKey(newTestUser(t), key.ID); !isErrorCode(err, "api_key_not_found") {
+		t.Errorf("RevokeAPIKey() by another user error = %v, want api_key_not_found", err)
+	}
+
+	revoked, err := s.RevokeAPIKey(userID, key.ID)
+	if err != nil || revoked.RevokedAt == nil {
+		t.Fatalf("RevokeAPIKey() = %+v, %v", revoked, err)
+	}
+
+	// Revoking again keeps the first time
+	first := getAPIKey(t, key.ID).RevokedAt
+	again, err := s.RevokeAPIKey(userID, key.ID)
+	if err != nil || !again.RevokedAt.Equal(*first) {
+		t.Errorf("RevokeAPIKey() again = %v, %v, want revoked at %v", again.RevokedAt, err, first)
+	}
+
+	keys, err := s.GetAPIKeys(userID)
+	if err != nil || len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Errorf("GetAPIKeys() = %+v, %v, want the revoked key", keys, err)
+	}
+}
+
+func isErrorCode(err error, code string) bool {
+	var serr *Error
+	return errors.As(err, &serr) && serr.Code == code
+}
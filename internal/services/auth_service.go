@@ -0,0 +1,349 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jayasaleh/todo-list/be/internal/config"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+var (
+	errEmailTaken          = Conflict("email_taken", "email already registered")
+	errInvalidCredentials  = Unauthorized("invalid_credentials", "invalid email or password")
+	errInvalidRefreshToken = Unauthorized("invalid_refresh_token", "invalid or expired refresh token")
+)
+
+type AuthService struct {
+	db              *gorm.DB
+	jwtSecret       []byte
+	jwtIssuer       string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// AccessClaims are the JWT claims carried by an access token
+type AccessClaims struct {
+	jwt.RegisteredClaims
+}
+
+// TokenPair is the result of a successful login or refresh
+type TokenPair struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// NewAuthService signs tokens with cfg.JWTSecret. The server refuses to start
+// without it (see config.Validate).
+func NewAuthService(db *gorm.DB, cfg *config.Config) *AuthService {
+	return &AuthService{
+		db:              db,
+		jwtSecret:       []byte(cfg.JWTSecret),
+		jwtIssuer:       cfg.JWTIssuer,
+		accessTokenTTL:  cfg.AccessTokenTTL,
+		refreshTokenTTL: cfg.RefreshTokenTTL,
+	}
+}
+
+// Register creates a new user with a bcrypt hashed password and a personal workspace
+func (s *AuthService) Register(req models.RegisterRequest) (*models.User, error) {
+	email := normalizeEmail(req.Email)
+
+	var count int64
+	if err := s.db.Model(&models.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if count > 0 {
+		return nil, errEmailTaken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := models.User{
+		Name:         strings.TrimSpace(req.Name),
+		Email:        email,
+		PasswordHash: string(hash),
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return errEmailTaken
+			}
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		_, err := createWorkspace(tx, personalWorkspaceName, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Login verifies the credentials and issues a new access/refresh token pair
+func (s *AuthService) Login(req models.LoginRequest) (*models.User, *TokenPair, error) {
+	var user models.User
+
+	if err := s.db.Where("email = ?", normalizeEmail(req.Email)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errInvalidCredentials
+		}
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, nil, errInvalidCredentials
+	}
+
+	familyID, err := generateToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.issueTokens(s.db, user.ID, familyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &user, tokens, nil
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// pair is issued in the same family. Presenting a token that was already
+// rotated is treated as theft and revokes the whole family.
+func (s *AuthService) Refresh(refreshToken string) (*models.User, *TokenPair, error) {
+	var user models.User
+	var tokens *TokenPair
+	reused := false
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			Where("token_hash = ?", hashToken(refreshToken)).
+			First(&current).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidRefreshToken
+			}
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+
+		if current.RevokedAt != nil {
+			reused = true
+			return errInvalidRefreshToken
+		}
+		if !current.ExpiresAt.After(time.Now()) || current.User == nil {
+			return errInvalidRefreshToken
+		}
+
+		now := time.Now()
+		if err := tx.Model(&current).Update("revoked_at", now).Error; err != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", err)
+		}
+
+		issued, err := s.issueTokens(tx, current.UserID, current.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		user = *current.User
+		tokens = issued
+		return nil
+	})
+
+	if reused {
+		if err := s.revokeFamilyByToken(refreshToken); err != nil {
+			log.Printf("Failed to revoke refresh token family: %v", err)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &user, tokens, nil
+}
+
+// Logout revokes the refresh token family of the given token for the user
+func (s *AuthService) Logout(userID uint, refreshToken string) error {
+	var token models.RefreshToken
+
+	err := s.db.Where("user_id = ? AND token_hash = ?", userID, hashToken(refreshToken)).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NotFound("refresh_token_not_found", "refresh token not found")
+		}
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return s.revokeFamily(token.FamilyID)
+}
+
+// LogoutAll revokes every refresh token of the user
+func (s *AuthService) LogoutAll(userID uint) error {
+	err := s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// ParseAccessToken validates the signature, issuer and expiry of an access
+// token and returns its claims
+func (s *AuthService) ParseAccessToken(token string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.jwtIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !parsed.Valid {
+		return nil, Unauthorized("invalid_token", "invalid or expired token")
+	}
+
+	return claims, nil
+}
+
+// UserIDFromClaims returns the user ID stored in the subject claim
+func UserIDFromClaims(claims *AccessClaims) (uint, error) {
+	id, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || id == 0 {
+		return 0, Unauthorized("invalid_token", "invalid token subject")
+	}
+	return uint(id), nil
+}
+
+// NewAccessToken signs an access token for the user. Access tokens are not
+// stored, so the router tests use it to authenticate without a database.
+func (s *AuthService) NewAccessToken(userID uint) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.accessTokenTTL)
+
+	claims := AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.jwtIssuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+// issueTokens signs a new access token and stores a new refresh token
+func (s *AuthService) issueTokens(db *gorm.DB, userID uint, familyID string) (*TokenPair, error) {
+	now := time.Now()
+
+	accessToken, accessExpiresAt, err := s.NewAccessToken(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.refreshTokenTTL),
+	}
+
+	if err := db.Create(&record).Error; err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+func (s *AuthService) revokeFamilyByToken(refreshToken string) error {
+	var token models.RefreshToken
+
+	if err := s.db.Where("token_hash = ?", hashToken(refreshToken)).First(&token).Error; err != nil {
+		return err
+	}
+
+	return s.revokeFamily(token.FamilyID)
+}
+
+func (s *AuthService) revokeFamily(familyID string) error {
+	err := s.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// Get User by ID
+func (s *AuthService) GetUserByID(id uint) (*models.User, error) {
+	var user models.User
+
+	if err := s.db.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NotFound("user_not_found", "user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// generateToken returns a random 256-bit value encoded as hex
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken returns the SHA-256 hex digest stored in place of the raw token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
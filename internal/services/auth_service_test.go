@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/jayasaleh/todo-list/be/internal/config"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+func newTestAuthService(refreshTokenTTL time.Duration) *AuthService {
+	return NewAuthService(testDB, &config.Config{
+		JWTSecret:       "test-secret",
+		JWTIssuer:       "todo-list-test",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: refreshTokenTTL,
+	})
+}
+
+// registerAndLogin registers a user with a unique email and logs in
+func registerAndLogin(t *testing.T, s *AuthService) (*models.User, *TokenPair) {
+	t.Helper()
+
+	req := models.RegisterRequest{
+		Name:     "Test",
+		Email:    fmt.Sprintf("auth-%d-%d@example.com", time.Now().UnixNano(), testUsers.Add(1)),
+		Password: "password123",
+	}
+	if _, err := s.Register(req); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	user, tokens, err := s.Login(models.LoginRequest{Email: req.Email, Password: req.Password})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	return user, tokens
+}
+
+func TestAuthLoginOnEngine(t *testing.T) {
+	s := newTestAuthService(time.Hour)
+	user, tokens := registerAndLogin(t, s)
+
+	claims, err := s.ParseAccessToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseAccessToken() error = %v", err)
+	}
+	if id, err := UserIDFromClaims(claims); err != nil || id != user.ID {
+		t.Errorf("UserIDFromClaims() = %d, %v, want %d", id, err, user.ID)
+	}
+
+	_, _, err = s.Login(models.LoginRequest{Email: user.Email, Password: "wrong-password"})
+	if !errors.Is(err, errInvalidCredentials) {
+		t.Errorf("Login() with a wrong password error = %v, want errInvalidCredentials", err)
+	}
+	if _, err := s.Register(models.RegisterRequest{Name: "Test", Email: " " + user.Email, Password: "password123"}); !errors.Is(err, errEmailTaken) {
+		t.Errorf("Register() with a taken email error = %v, want errEmailTaken", err)
+	}
+}
+
+func TestAuthRefreshRotatesOnEngine(t *testing.T) {
+	s := newTestAuthService(time.Hour)
+	user, first := registerAndLogin(t, s)
+
+	refreshedUser, second, err := s.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if refreshedUser.ID != user.ID || second.RefreshToken == first.RefreshToken {
+		t.Fatalf("Refresh() = user %d, same token %v", refreshedUser.ID, second.RefreshToken == first.RefreshToken)
+	}
+
+	// A second login starts another family, which reuse must not touch
+	_, other, err := s.Login(models.LoginRequest{Email: user.Email, Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	// Replaying the rotated token revokes the whole family, including the
+	// token that replaced it
+	if _, _, err := s.Refresh(first.RefreshToken); !errors.Is(err, errInvalidRefreshToken) {
+		t.Fatalf("Refresh() of a rotated token error = %v, want errInvalidRefreshToken", err)
+	}
+	if _, _, err := s.Refresh(second.RefreshToken); !errors.Is(err, errInvalidRefreshToken) {
+		t.Errorf("Refresh() after reuse error = %v, want errInvalidRefreshToken", err)
+	}
+
+	var family []models.RefreshToken
+	if err := testDB.Where("token_hash IN ?", []string{hashToken(first.RefreshToken), hashToken(second.RefreshToken)}).Find(&family).Error; err != nil {
+		t.Fatalf("failed to get refresh tokens: %v", err)
+	}
+	if len(family) != 2 {
+		t.Fatalf("family = %d tokens, want 2", len(family))
+	}
+	for _, token := range family {
+		if token.RevokedAt == nil {
+			t.Errorf("token %d of the reused family is not revoked", token.ID)
+		}
+	}
+
+	if _, _, err := s.Refresh(other.RefreshToken); err != nil {
+		t.Errorf("Refresh() of another family error = %v", err)
+	}
+}
+
+func TestAuthRefreshRejectsOnEngine(t *testing.T) {
+	t.Run("expired", func(t *testing.T) {
+		s := newTestAuthService(-time.Minute)
+		_, tokens := registerAndLogin(t, s)
+
+		if _, _, err := s.Refresh(tokens.RefreshToken); !errors.Is(err, errInvalidRefreshToken) {
+			t.Errorf("Refresh() error = %v, want errInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		s := newTestAuthService(time.Hour)
+
+		if _, _, err := s.Refresh("not-a-token"); !errors.Is(err, errInvalidRefreshToken) {
+			t.Errorf("Refresh() error = %v, want errInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("logged out", func(t *testing.T) {
+		s := newTestAuthService(time.Hour)
+		user, tokens := registerAndLogin(t, s)
+
+		if err := s.Logout(user.ID, tokens.RefreshToken); err != nil {
+			t.Fatalf("Logout() error = %v", err)
+		}
+		if _, _, err := s.Refresh(tokens.RefreshToken); !errors.Is(err, errInvalidRefreshToken) {
+			t.Errorf("Refresh() error = %v, want errInvalidRefreshToken", err)
+		}
+	})
+}
+
+func TestParseAccessTokenRejects(t *testing.T) {
+	s := NewAuthService(nil, &config.Config{JWTSecret: "test-secret", JWTIssuer: "todo-list-test", AccessTokenTTL: time.Minute})
+
+	now := time.Now()
+	valid := jwt.RegisteredClaims{
+		Issuer:    "todo-list-test",
+		Subject:   strconv.Itoa(1),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(method, AccessClaims{RegisteredClaims: claims}).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString() error = %v", err)
+		}
+		return token
+	}
+	with := func(change func(*jwt.RegisteredClaims)) jwt.RegisteredClaims {
+		claims := valid
+		change(&claims)
+		return claims
+	}
+
+	if _, err := s.ParseAccessToken(sign(jwt.SigningMethodHS256, []byte("test-secret"), valid)); err != nil {
+		t.Fatalf("ParseAccessToken() of a valid token error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"other algorithm", sign(jwt.SigningMethodHS512, []byte("test-secret"), valid)},
+		{"none algorithm", sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid)},
+		{"other key", sign(jwt.SigningMethodHS256, []byte("other-secret"), valid)},
+		{"other issuer", sign(jwt.SigningMethodHS256, []byte("test-secret"), with(func(c *jwt.RegisteredClaims) { c.Issuer = "someone-else" }))},
+		{"expired", sign(jwt.SigningMethodHS256, []byte("test-secret"), with(func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) }))},
+		{"no expiry", sign(jwt.SigningMethodHS256, []byte("test-secret"), with(func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil }))},
+		{"malformed", "not.a.token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.ParseAccessToken(tt.token); !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("ParseAccessToken() error = %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+}
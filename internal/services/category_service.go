@@ -6,49 +6,85 @@ import (
 
 	"gorm.io/gorm"
 
-	"github.com/jayasaleh/todo-list/be/internal/database"
 	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
 )
 
+// CategoryService is the GORM implementation of repository.CategoryRepository
 type CategoryService struct {
 	db *gorm.DB
 }
 
-func NewCategoryService() *CategoryService {
+var _ repository.CategoryRepository = (*CategoryService)(nil)
+
+func NewCategoryService(db *gorm.DB) *CategoryService {
 	return &CategoryService{
-		db: database.GetDB(),
+		db: db,
 	}
 }
 
 // Create Category
-func (s *CategoryService) CreateCategory(req models.CreateCategoryRequest) (*models.Category, error) {
+func (s *CategoryService) CreateCategory(userID uint, req models.CreateCategoryRequest) (*models.Category, error) {
 	color := req.Color
 	if color == "" {
 		color = "#3B82F6"
 	}
 
+	// Default to the user's personal workspace
+	var workspaceID uint
+	if req.WorkspaceID != nil {
+		workspaceID = *req.WorkspaceID
+	} else {
+		id, err := personalWorkspaceID(s.db, userID)
+		if err != nil {
+			return nil, err
+		}
+		workspaceID = id
+	}
+
+	role, err := getWorkspaceRole(s.db, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrWorkspaceNotFound
+	}
+	if !role.CanWrite() {
+		return nil, ErrInsufficientPermissions
+	}
+
 	category := models.Category{
-		Name:  req.Name,
-		Color: color,
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Name:        req.Name,
+		Color:       color,
 	}
 
-	if err := s.db.Create(&category).Error; err != nil {
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			return nil, errors.New("category name already exists")
+	err = realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Create(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrCategoryNameTaken
+			}
+			return fmt.Errorf("failed to create category: %w", err)
 		}
-		return nil, fmt.Errorf("failed to create category: %w", err)
+
+		return emitCategoryEvent(tx, models.EventCategoryCreated, &category)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &category, nil
 }
 
 // Get Category by ID
-func (s *CategoryService) GetCategoryByID(id uint) (*models.Category, error) {
+func (s *CategoryService) GetCategoryByID(userID, id uint) (*models.Category, error) {
 	var category models.Category
 
-	if err := s.db.First(&category, id).Error; err != nil {
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, ErrCategoryNotFound
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -57,10 +93,15 @@ func (s *CategoryService) GetCategoryByID(id uint) (*models.Category, error) {
 }
 
 // Get All Categories
-func (s *CategoryService) GetAllCategories() ([]models.Category, error) {
+func (s *CategoryService) GetAllCategories(userID, workspaceID uint) ([]models.Category, error) {
 	var categories []models.Category
 
-	if err := s.db.Find(&categories).Error; err != nil {
+	query := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID))
+	if workspaceID != 0 {
+		query = query.Where("workspace_id = ?", workspaceID)
+	}
+
+	if err := query.Find(&categories).Error; err != nil {
 		return nil, fmt.Errorf("failed to get categories: %w", err)
 	}
 
@@ -68,16 +109,20 @@ func (s *CategoryService) GetAllCategories() ([]models.Category, error) {
 }
 
 // Update Category
-func (s *CategoryService) UpdateCategory(id uint, req models.UpdateCategoryRequest) (*models.Category, error) {
+func (s *CategoryService) UpdateCategory(userID, id uint, req models.UpdateCategoryRequest) (*models.Category, error) {
 	var category models.Category
 
-	if err := s.db.First(&category, id).Error; err != nil {
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, ErrCategoryNotFound
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
 
+	if err := requireWorkspaceWrite(s.db, category.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
 	if req.Name != nil {
 		category.Name = *req.Name
 	}
@@ -85,39 +130,104 @@ func (s *CategoryService) UpdateCategory(id uint, req models.UpdateCategoryReque
 		category.Color = *req.Color
 	}
 
-	if err := s.db.Save(&category).Error; err != nil {
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			return nil, errors.New("category name already exists")
+	err := realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Save(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrCategoryNameTaken
+			}
+			return fmt.Errorf("failed to update category: %w", err)
 		}
-		return nil, fmt.Errorf("failed to update category: %w", err)
+
+		return emitCategoryEvent(tx, models.EventCategoryUpdated, &category)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &category, nil
 }
 
-// Delete Category
-func (s *CategoryService) DeleteCategory(id uint) error {
+// Delete Category moves the category to the trash. Its todos are handled by
+// the strategy: restrict refuses to delete a category that is still used,
+// reassign moves the todos to the target category and cascade moves them to
+// the trash together with the category.
+func (s *CategoryService) DeleteCategory(userID, id uint, params models.DeleteCategoryParams) error {
+	strategy := params.Strategy
+	if strategy == "" {
+		strategy = models.CategoryDeleteRestrict
+	}
+	if strategy != models.CategoryDeleteRestrict && strategy != models.CategoryDeleteReassign && strategy != models.CategoryDeleteCascade {
+		return ErrInvalidDeleteStrategy
+	}
+
 	var category models.Category
 
-	if err := s.db.First(&category, id).Error; err != nil {
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("category not found")
+			return ErrCategoryNotFound
 		}
 		return fmt.Errorf("failed to get category: %w", err)
 	}
 
-	var count int64
-	if err := s.db.Model(&models.Todo{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
-		return fmt.Errorf("failed to check category usage: %w", err)
+	if err := requireWorkspaceWrite(s.db, category.WorkspaceID, userID); err != nil {
+		return err
 	}
 
-	if count > 0 {
-		return errors.New("cannot delete category that is being used by todos")
+	var target models.Category
+	if strategy == models.CategoryDeleteReassign {
+		if params.Target == 0 {
+			return ErrReassignTargetRequired
+		}
+		if params.Target == category.ID {
+			return ErrReassignTargetSame
+		}
+		// Todos cannot move to another workspace
+		if err := s.db.Where("workspace_id = ?", category.WorkspaceID).First(&target, params.Target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReassignTargetNotFound
+			}
+			return fmt.Errorf("failed to get target category: %w", err)
+		}
 	}
 
-	if err := s.db.Delete(&category).Error; err != nil {
-		return fmt.Errorf("failed to delete category: %w", err)
-	}
+	return realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		// Todos in the trash are not counted; restoring one restores the category
+		var todos []models.Todo
+		if err := tx.Where("category_id = ?", category.ID).Order("id ASC").Find(&todos).Error; err != nil {
+			return fmt.Errorf("failed to check category usage: %w", err)
+		}
+
+		switch strategy {
+		case models.CategoryDeleteRestrict:
+			if len(todos) > 0 {
+				return ErrCategoryInUse
+			}
+
+		case models.CategoryDeleteReassign:
+			// Todos in the trash move as well, so that restoring them does not
+			// bring back the deleted category
+			if err := tx.Unscoped().Model(&models.Todo{}).Where("category_id = ?", category.ID).Update("category_id", target.ID).Error; err != nil {
+				return fmt.Errorf("failed to reassign todos: %w", err)
+			}
+			for i := range todos {
+				todos[i].CategoryID = target.ID
+				if err := emitTodoEvent(tx, models.EventTodoUpdated, &todos[i]); err != nil {
+					return err
+				}
+			}
+
+		case models.CategoryDeleteCascade:
+			for i := range todos {
+				if err := deleteTodo(tx, &todos[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Delete(&category).Error; err != nil {
+			return fmt.Errorf("failed to delete category: %w", err)
+		}
 
-	return nil
+		return emitCategoryEvent(tx, models.EventCategoryDeleted, &category)
+	})
 }
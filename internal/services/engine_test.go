@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/jayasaleh/todo-list/be/internal/config"
+	"github.com/jayasaleh/todo-list/be/internal/database"
+	"github.com/jayasaleh/todo-list/be/internal/migrator"
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+	"github.com/jayasaleh/todo-list/be/migrations"
+)
+
+// testDB is the database of the tests that need one. TestMain picks the
+// engine with DB_DRIVER, so the same tests run on both: a SQLite file by
+// default, or with DB_DRIVER=postgres the database of the DB_* variables.
+var testDB *gorm.DB
+
+var testUsers atomic.Int64
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	cfg := &config.Config{DBDriver: config.DriverSQLite}
+	if os.Getenv("DB_DRIVER") == config.DriverPostgres {
+		cfg = config.LoadConfig()
+	} else {
+		dir, err := os.MkdirTemp("", "todo-list-test")
+		if err != nil {
+			log.Printf("Failed to create test directory: %v", err)
+			return 1
+		}
+		defer os.RemoveAll(dir)
+		cfg.DBPath = filepath.Join(dir, "test.db")
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Printf("Failed to connect to the test database: %v", err)
+		return 1
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+
+	source, err := migrations.ForDriver(cfg.DBDriver)
+	if err != nil {
+		log.Printf("Failed to load migrations: %v", err)
+		return 1
+	}
+	mig, err := migrator.New(db, source)
+	if err != nil {
+		log.Printf("Failed to load migrations: %v", err)
+		return 1
+	}
+	if _, err := mig.Up(context.Background()); err != nil {
+		log.Printf("Failed to migrate the test database: %v", err)
+		return 1
+	}
+
+	testDB = db
+	return m.Run()
+}
+
+// newTestUser registers a user with a personal workspace. Emails are unique
+// per run, so tests also pass on a Postgres database that was used before.
+func newTestUser(t *testing.T) uint {
+	t.Helper()
+
+	auth := NewAuthService(testDB, &config.Config{JWTSecret: "test-secret"})
+	user, err := auth.Register(models.RegisterRequest{
+		Name:     "Test",
+		Email:    fmt.Sprintf("user-%d-%d@example.com", time.Now().UnixNano(), testUsers.Add(1)),
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return user.ID
+}
+
+// newTestCategory creates a category in the personal workspace of the user
+func newTestCategory(t *testing.T, userID uint, name string) *models.Category {
+	t.Helper()
+
+	category, err := NewCategoryService(testDB).CreateCategory(userID, models.CreateCategoryRequest{Name: name})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	return category
+}
+
+// newTestTodo creates a todo
+func newTestTodo(t *testing.T, userID uint, req models.CreateTodoRequest) *models.Todo {
+	t.Helper()
+
+	todo, err := NewTodoService(testDB).CreateTodo(userID, req)
+	if err != nil {
+		t.Fatalf("CreateTodo(%q) error = %v", req.Title, err)
+	}
+	return todo
+}
+
+// newListTodos creates the todos the sort, cursor and query tests list, in
+// this order, and completes Bravo last:
+//
+//	Alpha    high    Home  due in 1 day, tag urgent, description
+//	Bravo    low     Work  completed
+//	Charlie  medium  Home  due in 3 days, recurring, reminder
+//	Delta    high    Work  checklist item
+//	Echo     low     Home  due 2 days ago
+func newListTodos(t *testing.T) uint {
+	t.Helper()
+
+	userID := newTestUser(t)
+	home := newTestCategory(t, userID, "Home")
+	work := newTestCategory(t, userID, "Work")
+	tag, err := NewTagService(testDB).CreateTag(userID, models.CreateTagRequest{Name: "urgent"})
+	if err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+
+	now := time.Now()
+	inOneDay, inThreeDays, twoDaysAgo := now.Add(24*time.Hour), now.Add(72*time.Hour), now.Add(-48*time.Hour)
+	newTestTodo(t, userID, models.CreateTodoRequest{Title: "Alpha", Description: "Quarterly report for the board", CategoryID: home.ID, Priority: models.PriorityHigh, DueDate: &inOneDay, TagIDs: []uint{tag.ID}})
+	bravo := newTestTodo(t, userID, models.CreateTodoRequest{Title: "Bravo", CategoryID: work.ID, Priority: models.PriorityLow})
+	newTestTodo(t, userID, models.CreateTodoRequest{
+		Title:           "Charlie",
+		CategoryID:      home.ID,
+		Priority:        models.PriorityMedium,
+		DueDate:         &inThreeDays,
+		Recurrence:      &models.RecurrenceRule{Frequency: models.FrequencyDaily, Interval: 1},
+		ReminderOffsets: []int{60},
+	})
+	delta := newTestTodo(t, userID, models.CreateTodoRequest{Title: "Delta", CategoryID: work.ID, Priority: models.PriorityHigh})
+	newTestTodo(t, userID, models.CreateTodoRequest{Title: "Echo", CategoryID: home.ID, Priority: models.PriorityLow, DueDate: &twoDaysAgo})
+
+	if _, err := NewTodoItemService(testDB).CreateItem(userID, delta.ID, models.CreateTodoItemRequest{Title: "Call"}); err != nil {
+		t.Fatalf("CreateItem() error = %v", err)
+	}
+	if _, err := NewTodoService(testDB).ToggleComplete(userID, bravo.ID); err != nil {
+		t.Fatalf("ToggleComplete() error = %v", err)
+	}
+	return userID
+}
+
+// todoTitles returns the titles of the todos in order
+func todoTitles(todos []models.Todo) []string {
+	titles := make([]string, len(todos))
+	for i, todo := range todos {
+		titles[i] = todo.Title
+	}
+	return titles
+}
+
+func TestGetTodosOnEngine(t *testing.T) {
+	userID := newTestUser(t)
+	category := newTestCategory(t, userID, "Home")
+	s := NewTodoService(testDB)
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+	newTestTodo(t, userID, models.CreateTodoRequest{Title: "Buy groceries", CategoryID: category.ID, Priority: models.PriorityMedium, DueDate: &tomorrow})
+	newTestTodo(t, userID, models.CreateTodoRequest{Title: "Pay rent", CategoryID: category.ID, Priority: models.PriorityHigh})
+	walk := newTestTodo(t, userID, models.CreateTodoRequest{Title: "Walk the dog", CategoryID: category.ID, Priority: models.PriorityLow})
+	if _, err := s.ToggleComplete(userID, walk.ID); err != nil {
+		t.Fatalf("ToggleComplete() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params models.PaginationParams
+		want   []string
+	}{
+		{
+			name:   "sort by priority",
+			params: models.PaginationParams{Sort: "-priority"},
+			want:   []string{"Pay rent", "Buy groceries", "Walk the dog"},
+		},
+		{
+			name:   "filters",
+			params: models.PaginationParams{Priorities: []models.Priority{models.PriorityHigh, models.PriorityLow}, Sort: "title"},
+			want:   []string{"Pay rent", "Walk the dog"},
+		},
+		{
+			name:   "query",
+			params: models.PaginationParams{Query: "-is:completed due:7d"},
+			want:   []string{"Buy groceries"},
+		},
+		{
+			name:   "search",
+			params: models.PaginationParams{Search: "groceries"},
+			want:   []string{"Buy groceries"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos, _, err := s.GetTodos(userID, tt.params)
+			if err != nil {
+				t.Fatalf("GetTodos() error = %v", err)
+			}
+			if got := todoTitles(todos); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("GetTodos() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("cursor", func(t *testing.T) {
+		params := models.PaginationParams{Pagination: "cursor", Limit: 2, Sort: "title"}
+		first, page, err := s.GetTodos(userID, params)
+		if err != nil {
+			t.Fatalf("GetTodos() error = %v", err)
+		}
+		if !page.HasMore || page.NextCursor == "" {
+			t.Fatalf("first page = %+v, want a next cursor", page)
+		}
+
+		params.Cursor = page.NextCursor
+		second, page, err := s.GetTodos(userID, params)
+		if err != nil {
+			t.Fatalf("GetTodos() error = %v", err)
+		}
+		got := append(todoTitles(first), todoTitles(second)...)
+		want := []string{"Buy groceries", "Pay rent", "Walk the dog"}
+		if fmt.Sprint(got) != fmt.Sprint(want) || page.HasMore {
+			t.Errorf("pages = %q (has more %v), want %q", got, page.HasMore, want)
+		}
+	})
+}
+
+func TestBulkAllOrNothingRollsBackOnEngine(t *testing.T) {
+	userID := newTestUser(t)
+	category := newTestCategory(t, userID, "Work")
+
+	due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	report := newTestTodo(t, userID, models.CreateTodoRequest{Title: "Report", CategoryID: category.ID, Priority: models.PriorityLow, DueDate: &due})
+	standup := newTestTodo(t, userID, models.CreateTodoRequest{
+		Title:      "Standup",
+		CategoryID: category.ID,
+		Priority:   models.PriorityLow,
+		DueDate:    &due,
+		Recurrence: &models.RecurrenceRule{Frequency: models.FrequencyDaily, Interval: 1},
+	})
+
+	sub, _, _ := realtime.GetHub().Subscribe(0)
+	defer sub.Close()
+
+	// Recurring todos need a due date, so the second todo fails
+	results, rolledBack, err := NewTodoService(testDB).BulkUpdate(userID, models.BulkTodoRequest{
+		IDs:          []uint{report.ID, standup.ID},
+		Action:       models.BulkActionSetDueDate,
+		AllOrNothing: true,
+	})
+	if err != nil || !rolledBack {
+		t.Fatalf("BulkUpdate() = %v, %v, want a rollback", rolledBack, err)
+	}
+	if results[0].Status != models.BulkStatusRolledBack || results[1].Status != models.BulkStatusFailed {
+		t.Errorf("BulkUpdate() results = %+v", results)
+	}
+
+	got, err := NewTodoService(testDB).GetTodoByID(userID, report.ID)
+	if err != nil {
+		t.Fatalf("GetTodoByID() error = %v", err)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(due) {
+		t.Errorf("due date after rollback = %v, want %v", got.DueDate, due)
+	}
+
+	// Clients must not hear about the change to the first todo
+	select {
+	case event := <-sub.C:
+		t.Errorf("event %s %s after rollback", event.Type, event.Data)
+	default:
+	}
+}
+
+func TestTrashOnEngine(t *testing.T) {
+	userID := newTestUser(t)
+	category := newTestCategory(t, userID, "Errands")
+	todo := newTestTodo(t, userID, models.CreateTodoRequest{Title: "Post letter", CategoryID: category.ID, Priority: models.PriorityLow})
+
+	if err := NewCategoryService(testDB).DeleteCategory(userID, category.ID, models.DeleteCategoryParams{Strategy: models.CategoryDeleteCascade}); err != nil {
+		t.Fatalf("DeleteCategory() error = %v", err)
+	}
+
+	trash := NewTrashService(testDB, time.Hour)
+	items, _, err := trash.GetTrash(userID, models.TrashParams{})
+	if err != nil {
+		t.Fatalf("GetTrash() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("GetTrash() = %+v, want the category and its todo", items)
+	}
+
+	if _, err := trash.RestoreTodo(userID, todo.ID, false); !errors.Is(err, errCategoryInTrash) {
+		t.Errorf("RestoreTodo() error = %v, want errCategoryInTrash", err)
+	}
+	restored, err := trash.RestoreTodo(userID, todo.ID, true)
+	if err != nil {
+		t.Fatalf("RestoreTodo() error = %v", err)
+	}
+	if restored.ID != todo.ID {
+		t.Errorf("RestoreTodo() = %+v", restored)
+	}
+	if _, err := NewCategoryService(testDB).GetCategoryByID(userID, category.ID); err != nil {
+		t.Errorf("GetCategoryByID() after restore error = %v", err)
+	}
+}
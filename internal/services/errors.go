@@ -0,0 +1,85 @@
+package services
+
+import "errors"
+
+// Kinds of the errors returned by the services. Every domain error wraps one
+// of them, so callers can check the kind with errors.Is; httperr.Error maps the
+// kind to an HTTP status. Errors of no kind are internal errors.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is a domain error. Message is safe to show to clients and Code is a
+// machine-readable name of the error, e.g. "todo_not_found".
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	// Fields lists the invalid request fields of a validation error
+	Fields []FieldError
+}
+
+// FieldError describes why a request field is invalid
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message, Fields: fields}
+}
+
+// InvalidField returns a validation error of a single request field
+func InvalidField(code, field, message string) *Error {
+	return Validation(code, message, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Errors returned by several services and the in-memory repositories
+var (
+	ErrTodoNotFound            = NotFound("todo_not_found", "todo not found")
+	ErrCategoryNotFound        = NotFound("category_not_found", "category not found")
+	ErrWorkspaceNotFound       = NotFound("workspace_not_found", "workspace not found")
+	ErrInsufficientPermissions = Forbidden("insufficient_permissions", "insufficient workspace permissions")
+	ErrInvalidPriority         = InvalidField("invalid_priority", "priority", "invalid priority value. Must be 'high', 'medium', or 'low'")
+	ErrTodoNotRecurring        = Validation("todo_not_recurring", "todo is not recurring")
+
+	// ErrTodoCategoryNotFound is returned when the category_id of a todo
+	// does not exist
+	ErrTodoCategoryNotFound = InvalidField("category_not_found", "category_id", "category not found")
+
+	ErrCategoryNameTaken      = Conflict("category_name_taken", "category name already exists")
+	ErrCategoryInUse          = Conflict("category_in_use", "cannot delete category that is being used by todos")
+	ErrInvalidDeleteStrategy  = InvalidField("invalid_strategy", "strategy", "invalid strategy. Must be 'restrict', 'reassign' or 'cascade'")
+	ErrReassignTargetRequired = InvalidField("target_required", "target", "target is required for reassign")
+	ErrReassignTargetSame     = InvalidField("invalid_target", "target", "target must be a different category")
+	ErrReassignTargetNotFound = InvalidField("target_not_found", "target", "target category not found")
+)
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+)
+
+// emitEvent records a change for webhook subscribers and realtime clients.
+// It must be called with the transaction of the change.
+func emitEvent(db *gorm.DB, event realtime.Event, recordID uint, data interface{}) error {
+	if err := enqueueWebhookEvent(db, event.WorkspaceID, event.Type, data); err != nil {
+		return err
+	}
+	return realtime.Publish(db, event, recordID, data)
+}
+
+// emitTodoEvent emits a todo event with the current state of the todo
+func emitTodoEvent(db *gorm.DB, event string, todo *models.Todo) error {
+	data := models.ToTodoResponse(*todo)
+
+	// Deleted todos can no longer be loaded, so they are sent as they were
+	if event != models.EventTodoDeleted && event != models.EventTodoPurged {
+		var current models.Todo
+		if err := preloadTodo(db).First(&current, todo.ID).Error; err != nil {
+			return fmt.Errorf("failed to get todo: %w", err)
+		}
+		data = models.ToTodoResponse(current)
+	}
+
+	return emitEvent(db, realtime.Event{Type: event, WorkspaceID: todo.WorkspaceID, CategoryID: todo.CategoryID}, todo.ID, data)
+}
+
+// emitCategoryEvent emits a category event
+func emitCategoryEvent(db *gorm.DB, event string, category *models.Category) error {
+	return emitEvent(db, realtime.Event{Type: event, WorkspaceID: category.WorkspaceID, CategoryID: category.ID}, category.ID, models.ToCategoryResponse(*category))
+}
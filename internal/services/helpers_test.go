@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB returns a Postgres session that builds statements without a
+// database, for tests of the generated SQL. The *OnEngine tests check the
+// results of the same statements on a real database.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry run session: %v", err)
+	}
+	return db
+}
+
+// dryRunSQL returns the SQL of an executed dry run statement
+func dryRunSQL(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+
+	if db.Error != nil {
+		t.Fatalf("failed to build statement: %v", db.Error)
+	}
+	return db.Statement.SQL.String()
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+)
+
+type PresenceService struct {
+	db *gorm.DB
+}
+
+func NewPresenceService(db *gorm.DB) *PresenceService {
+	return &PresenceService{
+		db: db,
+	}
+}
+
+// Update Presence of a connection on a todo. Viewing needs access to the
+// todo, editing needs write access to its workspace.
+func (s *PresenceService) UpdatePresence(userID, todoID uint, state string, presence realtime.PresenceUpdate) (*realtime.PresenceUpdate, error) {
+	if state != realtime.PresenceViewing && state != realtime.PresenceEditing {
+		return nil, InvalidField("invalid_presence_state", "state", "invalid presence state. Must be 'viewing', 'editing', or 'idle'")
+	}
+
+	todo, err := getTodoForUser(s.db, userID, todoID, state == realtime.PresenceEditing)
+	if err != nil {
+		return nil, err
+	}
+
+	presence.TodoID = todo.ID
+	presence.WorkspaceID = todo.WorkspaceID
+	presence.CategoryID = todo.CategoryID
+	presence.State = state
+
+	return &presence, s.RefreshPresence(&presence)
+}
+
+// Refresh Presence before it expires
+func (s *PresenceService) RefreshPresence(presence *realtime.PresenceUpdate) error {
+	presence.ExpiresAt = time.Now().Add(realtime.PresenceTTL)
+	return realtime.PublishPresence(s.db, *presence)
+}
+
+// Clear Presence when the user leaves the todo or disconnects
+func (s *PresenceService) ClearPresence(presence realtime.PresenceUpdate) error {
+	presence.State = realtime.PresenceIdle
+	return realtime.PublishPresence(s.db, presence)
+}
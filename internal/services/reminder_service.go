@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/notifier"
+)
+
+const (
+	// maxReminderOffset is the earliest reminder allowed: one year before the due date
+	maxReminderOffset   = 365 * 24 * 60
+	maxRemindersPerTodo = 10
+
+	reminderBatchSize   = 50
+	reminderLease       = 5 * time.Minute
+	reminderMaxAttempts = 5
+)
+
+// normalizeReminderOffsets validates, deduplicates and sorts reminder offsets
+func normalizeReminderOffsets(offsets []int) ([]int, error) {
+	seen := make(map[int]bool, len(offsets))
+	var normalized []int
+	for _, offset := range offsets {
+		if offset < 1 || offset > maxReminderOffset {
+			return nil, InvalidField("invalid_reminder_offsets", "reminder_offsets", fmt.Sprintf("reminder offsets must be between 1 and %d minutes", maxReminderOffset))
+		}
+		if !seen[offset] {
+			seen[offset] = true
+			normalized = append(normalized, offset)
+		}
+	}
+	if len(normalized) > maxRemindersPerTodo {
+		return nil, InvalidField("too_many_reminders", "reminder_offsets", fmt.Sprintf("a todo can have at most %d reminders", maxRemindersPerTodo))
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(normalized)))
+	return normalized, nil
+}
+
+// replaceReminders replaces the reminders of a saved todo with the given offsets
+func replaceReminders(db *gorm.DB, todo *models.Todo, offsets []int) error {
+	if err := db.Where("todo_id = ?", todo.ID).Delete(&models.Reminder{}).Error; err != nil {
+		return fmt.Errorf("failed to delete reminders: %w", err)
+	}
+	if len(offsets) == 0 {
+		return nil
+	}
+	if todo.DueDate == nil {
+		return InvalidField("due_date_required", "due_date", "due_date is required for reminders")
+	}
+
+	reminders := make([]models.Reminder, len(offsets))
+	for i, offset := range offsets {
+		reminders[i] = models.Reminder{
+			TodoID:        todo.ID,
+			OffsetMinutes: offset,
+			RemindAt:      todo.DueDate.Add(-time.Duration(offset) * time.Minute),
+		}
+	}
+
+	if err := db.Create(&reminders).Error; err != nil {
+		return fmt.Errorf("failed to create reminders: %w", err)
+	}
+	return nil
+}
+
+// reminderOffsets returns the offsets of the reminders of a todo
+func reminderOffsets(db *gorm.DB, todoID uint) ([]int, error) {
+	var offsets []int
+	if err := db.Model(&models.Reminder{}).Where("todo_id = ?", todoID).Order("offset_minutes DESC").Pluck("offset_minutes", &offsets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	return offsets, nil
+}
+
+// rescheduleReminders recreates the reminders of a todo after its due date
+// changed, so reminders that were already sent fire again for the new date
+func rescheduleReminders(db *gorm.DB, todo *models.Todo) error {
+	offsets, err := reminderOffsets(db, todo.ID)
+	if err != nil {
+		return err
+	}
+	return replaceReminders(db, todo, offsets)
+}
+
+// ReminderScheduler delivers due reminders in the background. Several server
+// replicas can run it at the same time: reminders are claimed with
+// SELECT ... FOR UPDATE SKIP LOCKED and a lease, so each one is delivered by
+// a single replica.
+type ReminderScheduler struct {
+	db       *gorm.DB
+	notifier notifier.Notifier
+	interval time.Duration
+}
+
+func NewReminderScheduler(db *gorm.DB, n notifier.Notifier, interval time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{
+		db:       db,
+		notifier: n,
+		interval: interval,
+	}
+}
+
+// Run polls for due reminders until the context is cancelled
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		log.Println("Reminder scheduler disabled")
+		return
+	}
+
+	log.Printf("Reminder scheduler started (interval %s)", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil {
+			log.Printf("Reminder scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce claims and delivers one batch of due reminders and returns the
+// number of reminders that were delivered
+func (s *ReminderScheduler) RunOnce(ctx context.Context) (int, error) {
+	reminders, err := s.claim(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	// Reminders are claimed oldest first. When several reminders of the same
+	// todo are due at once (e.g. the todo was created close to its due date),
+	// only the most recent one is delivered and the older ones are skipped.
+	latest := make(map[uint]uint, len(reminders))
+	for _, reminder := range reminders {
+		latest[reminder.TodoID] = reminder.ID
+	}
+
+	delivered := 0
+	for _, reminder := range reminders {
+		if latest[reminder.TodoID] != reminder.ID {
+			if err := s.markSent(reminder); err != nil {
+				log.Printf("Failed to skip reminder %d: %v", reminder.ID, err)
+			}
+			continue
+		}
+
+		if err := s.deliver(ctx, reminder); err != nil {
+			log.Printf("Failed to deliver reminder %d: %v", reminder.ID, err)
+			continue
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// claim locks a batch of due reminders for this replica. Reminders are only
+// due while their todo is open and the due date has not passed yet.
+func (s *ReminderScheduler) claim(now time.Time) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "reminders"}, Options: "SKIP LOCKED"}).
+			Preload("Todo.User").
+			Joins("JOIN todos ON todos.id = reminders.todo_id AND todos.deleted_at IS NULL").
+			Where("reminders.sent_at IS NULL AND reminders.attempts < ? AND reminders.remind_at <= ?", reminderMaxAttempts, now).
+			Where("(reminders.locked_until IS NULL OR reminders.locked_until < ?)", now).
+			Where("todos.completed = ? AND todos.due_date > ?", false, now).
+			Order("reminders.remind_at ASC").
+			Limit(reminderBatchSize).
+			Find(&reminders).Error
+		if err != nil {
+			return fmt.Errorf("failed to get due reminders: %w", err)
+		}
+		if len(reminders) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(reminders))
+		for i, reminder := range reminders {
+			ids[i] = reminder.ID
+		}
+
+		if err := tx.Model(&models.Reminder{}).Where("id IN ?", ids).Update("locked_until", now.Add(reminderLease)).Error; err != nil {
+			return fmt.Errorf("failed to claim reminders: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+// deliver sends a claimed reminder and records the outcome. Failed reminders
+// are retried with a growing delay until reminderMaxAttempts is reached.
+func (s *ReminderScheduler) deliver(ctx context.Context, reminder models.Reminder) error {
+	todo := reminder.Todo
+	if todo == nil || todo.DueDate == nil {
+		return s.markSent(reminder)
+	}
+
+	n := notifier.Notification{
+		ReminderID: reminder.ID,
+		TodoID:     todo.ID,
+		TodoTitle:  todo.Title,
+		DueDate:    *todo.DueDate,
+		RemindAt:   reminder.RemindAt,
+		UserID:     todo.UserID,
+	}
+	if todo.User != nil {
+		n.UserName = todo.User.Name
+		n.UserEmail = todo.User.Email
+	}
+
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		retryAt := time.Now().Add(time.Duration(reminder.Attempts+1) * time.Minute)
+		updateErr := s.db.Model(&reminder).Updates(map[string]interface{}{
+			"attempts":     reminder.Attempts + 1,
+			"last_error":   err.Error(),
+			"locked_until": retryAt,
+		}).Error
+		if updateErr != nil {
+			log.Printf("Failed to record reminder failure: %v", updateErr)
+		}
+		return err
+	}
+
+	return s.markSent(reminder)
+}
+
+func (s *ReminderScheduler) markSent(reminder models.Reminder) error {
+	err := s.db.Model(&reminder).Updates(map[string]interface{}{
+		"sent_at":      time.Now(),
+		"locked_until": nil,
+		"last_error":   "",
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder as sent: %w", err)
+	}
+	return nil
+}
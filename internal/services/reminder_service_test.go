@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/notifier"
+)
+
+// recordingNotifier records the notifications of one todo and fails while err
+// is set. Other tests may leave due reminders behind, so their todos are
+// ignored.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	todoID uint
+	err    error
+	sent   []notifier.Notification
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n notifier.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n.TodoID != r.todoID {
+		return nil
+	}
+	r.sent = append(r.sent, n)
+	return r.err
+}
+
+func (r *recordingNotifier) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sent)
+}
+
+// newReminderTodo creates a todo due in 30 minutes with reminders 60 and 45
+// minutes before (both due) and 10 minutes before (not due yet)
+func newReminderTodo(t *testing.T) *models.Todo {
+	t.Helper()
+
+	userID := newTestUser(t)
+	category := newTestCategory(t, userID, "Reminders")
+	due := time.Now().Add(30 * time.Minute)
+	return newTestTodo(t, userID, models.CreateTodoRequest{
+		Title:           "Call the plumber",
+		CategoryID:      category.ID,
+		Priority:        models.PriorityMedium,
+		DueDate:         &due,
+		ReminderOffsets: []int{60, 45, 10},
+	})
+}
+
+// getReminders returns the reminders of a todo by offset
+func getReminders(t *testing.T, todoID uint) map[int]models.Reminder {
+	t.Helper()
+
+	var reminders []models.Reminder
+	if err := testDB.Where("todo_id = ?", todoID).Find(&reminders).Error; err != nil {
+		t.Fatalf("failed to get reminders: %v", err)
+	}
+	byOffset := make(map[int]models.Reminder, len(reminders))
+	for _, reminder := range reminders {
+		byOffset[reminder.OffsetMinutes] = reminder
+	}
+	return byOffset
+}
+
+func TestReminderSchedulerOnEngine(t *testing.T) {
+	todo := newReminderTodo(t)
+	n := &recordingNotifier{todoID: todo.ID}
+	s := NewReminderScheduler(testDB, n, time.Minute)
+
+	if _, err := s.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+
+	// Only the most recent due reminder is delivered
+	if len(n.sent) != 1 || n.sent[0].TodoTitle != "Call the plumber" || n.sent[0].UserEmail == "" {
+		t.Fatalf("notifications = %+v, want one for the todo", n.sent)
+	}
+	reminders := getReminders(t, todo.ID)
+	if n.sent[0].ReminderID != reminders[45].ID {
+		t.Errorf("delivered reminder %d, want %d (45 minutes before)", n.sent[0].ReminderID, reminders[45].ID)
+	}
+	if reminders[60].SentAt == nil || reminders[45].SentAt == nil {
+		t.Errorf("due reminders = %+v, want both marked as sent", reminders)
+	}
+	if reminders[10].SentAt != nil {
+		t.Errorf("reminder 10 minutes before = %+v, want it pending", reminders[10])
+	}
+
+	// Sent reminders do not fire again
+	if _, err := s.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if n.count() != 1 {
+		t.Errorf("notifications after a second run = %d, want 1", n.count())
+	}
+}
+
+func TestReminderLeaseOnEngine(t *testing.T) {
+	todo := newReminderTodo(t)
+	n := &recordingNotifier{todoID: todo.ID}
+	s := NewReminderScheduler(testDB, n, time.Minute)
+
+	// Another replica claimed the reminders and has not delivered them yet
+	now := time.Now()
+	claimed, err := s.claim(now)
+	if err != nil {
+		t.Fatalf("claim() error = %v", err)
+	}
+	mine := 0
+	for _, reminder := range claimed {
+		if reminder.TodoID == todo.ID {
+			mine++
+		}
+	}
+	if mine != 2 {
+		t.Fatalf("claim() = %d reminders of the todo, want 2", mine)
+	}
+
+	if _, err := s.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if n.count() != 0 {
+		t.Fatalf("notifications during the lease = %d, want 0", n.count())
+	}
+
+	// Once the lease expires another replica takes over
+	again, err := s.claim(now.Add(reminderLease + time.Second))
+	if err != nil {
+		t.Fatalf("claim() error = %v", err)
+	}
+	mine = 0
+	for _, reminder := range again {
+		if reminder.TodoID == todo.ID {
+			mine++
+		}
+	}
+	if mine != 2 {
+		t.Errorf("claim() after the lease = %d reminders of the todo, want 2", mine)
+	}
+}
+
+func TestReminderRetriesOnEngine(t *testing.T) {
+	todo := newReminderTodo(t)
+	n := &recordingNotifier{todoID: todo.ID, err: errors.New("mail server down")}
+	s := NewReminderScheduler(testDB, n, time.Minute)
+
+	reminderID := getReminders(t, todo.ID)[45].ID
+	for attempt := 1; attempt <= reminderMaxAttempts; attempt++ {
+		before := time.Now()
+		if _, err := s.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+		if n.count() != attempt {
+			t.Fatalf("attempt %d: notifications = %d", attempt, n.count())
+		}
+
+		var reminder models.Reminder
+		if err := testDB.First(&reminder, reminderID).Error; err != nil {
+			t.Fatalf("failed to get reminder: %v", err)
+		}
+		if reminder.Attempts != attempt || reminder.LastError != "mail server down" || reminder.SentAt != nil {
+			t.Fatalf("attempt %d: reminder = %+v", attempt, reminder)
+		}
+
+		// The delay grows by a minute with every attempt
+		wantRetry := before.Add(time.Duration(attempt) * time.Minute)
+		if reminder.LockedUntil == nil || reminder.LockedUntil.Before(wantRetry) || reminder.LockedUntil.After(wantRetry.Add(5*time.Second)) {
+			t.Errorf("attempt %d: retry at %v, want about %v", attempt, reminder.LockedUntil, wantRetry)
+		}
+
+		// Nothing is retried before the delay has passed
+		if _, err := s.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+		if n.count() != attempt {
+			t.Fatalf("attempt %d: retried before the delay", attempt)
+		}
+
+		// Let the delay pass
+		if err := testDB.Model(&models.Reminder{}).Where("id = ?", reminderID).Update("locked_until", before.Add(-time.Second)).Error; err != nil {
+			t.Fatalf("failed to expire the retry delay: %v", err)
+		}
+	}
+
+	if _, err := s.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if n.count() != reminderMaxAttempts {
+		t.Errorf("notifications = %d, want retries to stop after %d attempts", n.count(), reminderMaxAttempts)
+	}
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+)
+
+var (
+	errSavedViewNotFound  = NotFound("saved_view_not_found", "saved view not found")
+	errSavedViewNameTaken = Conflict("saved_view_name_taken", "saved view name already exists")
+	errBuiltInView        = Forbidden("built_in_view", "built-in views cannot be changed")
+)
+
+type SavedViewService struct {
+	db    *gorm.DB
+	todos repository.TodoRepository
+}
+
+// NewSavedViewService stores saved views in the database and runs views
+// against the given todo repository
+func NewSavedViewService(db *gorm.DB, todos repository.TodoRepository) *SavedViewService {
+	return &SavedViewService{
+		db:    db,
+		todos: todos,
+	}
+}
+
+// Get Views returns the built-in views followed by the user's saved views
+func (s *SavedViewService) GetViews(userID uint) ([]models.SavedView, error) {
+	var views []models.SavedView
+
+	if err := s.db.Where("user_id = ?", userID).Order("name ASC").Find(&views).Error; err != nil {
+		return nil, fmt.Errorf("failed to get saved views: %w", err)
+	}
+
+	return append(models.BuiltInViews(), views...), nil
+}
+
+// Get View by ID or built-in key
+func (s *SavedViewService) GetView(userID uint, id string) (*models.SavedView, error) {
+	for _, view := range models.BuiltInViews() {
+		if view.Key == id {
+			return &view, nil
+		}
+	}
+
+	viewID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, errSavedViewNotFound
+	}
+
+	var view models.SavedView
+	if err := s.db.Where("user_id = ?", userID).First(&view, viewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errSavedViewNotFound
+		}
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+
+	return &view, nil
+}
+
+// Create View
+func (s *SavedViewService) CreateView(userID uint, req models.CreateSavedViewRequest) (*models.SavedView, error) {
+	view := models.SavedView{
+		UserID:      userID,
+		Name:        req.Name,
+		WorkspaceID: req.WorkspaceID,
+		Search:      req.Search,
+		Query:       req.Query,
+		Sort:        req.Sort,
+		Pagination:  req.Pagination,
+		Limit:       req.Limit,
+	}
+	if req.Filters != nil {
+		if err := setViewFilters(&view, *req.Filters); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validateView(userID, &view); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errSavedViewNameTaken
+		}
+		return nil, fmt.Errorf("failed to create saved view: %w", err)
+	}
+
+	return &view, nil
+}
+
+// Update View
+func (s *SavedViewService) UpdateView(userID uint, id string, req models.UpdateSavedViewRequest) (*models.SavedView, error) {
+	view, err := s.GetView(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if view.BuiltIn() {
+		return nil, errBuiltInView
+	}
+
+	if req.Name != nil {
+		view.Name = *req.Name
+	}
+	if req.WorkspaceID != nil {
+		// 0 removes the workspace filter
+		view.WorkspaceID = req.WorkspaceID
+		if *req.WorkspaceID == 0 {
+			view.WorkspaceID = nil
+		}
+	}
+	if req.Search != nil {
+		view.Search = *req.Search
+	}
+	if req.Query != nil {
+		view.Query = *req.Query
+	}
+	if req.Sort != nil {
+		view.Sort = *req.Sort
+	}
+	if req.Pagination != nil {
+		view.Pagination = *req.Pagination
+	}
+	if req.Limit != nil {
+		view.Limit = *req.Limit
+	}
+	if req.Filters != nil {
+		if err := setViewFilters(view, *req.Filters); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validateView(userID, view); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Save(view).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errSavedViewNameTaken
+		}
+		return nil, fmt.Errorf("failed to update saved view: %w", err)
+	}
+
+	return view, nil
+}
+
+// Delete View
+func (s *SavedViewService) DeleteView(userID uint, id string) error {
+	view, err := s.GetView(userID, id)
+	if err != nil {
+		return err
+	}
+	if view.BuiltIn() {
+		return errBuiltInView
+	}
+
+	if err := s.db.Delete(view).Error; err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+
+	return nil
+}
+
+// Get View Todos runs the view through the todo repository. The page, limit,
+// cursor and include_total of the request override the stored spec so that
+// clients can page through the results.
+func (s *SavedViewService) GetViewTodos(userID uint, id string, paging models.PaginationParams) ([]models.Todo, *models.Pagination, error) {
+	view, err := s.GetView(userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := view.Params()
+	params.Page = paging.Page
+	params.Cursor = paging.Cursor
+	params.IncludeTotal = paging.IncludeTotal
+	if paging.Limit > 0 {
+		params.Limit = paging.Limit
+	}
+
+	return s.todos.GetTodos(userID, params)
+}
+
+// setViewFilters stores the filters in the view. Tags are normalized like the
+// tag filter of GET /api/todos and cannot contain commas, since the view
+// stores them as a comma separated list.
+func setViewFilters(view *models.SavedView, filters models.TodoFilters) error {
+	filters.Tags = tagFilterNames(filters.Tags)
+	for _, tag := range filters.Tags {
+		if !models.ValidateTagName(tag) {
+			return InvalidField("invalid_filter", "filters.tag", fmt.Sprintf("invalid tag filter %q", tag))
+		}
+	}
+
+	view.SetFilters(filters)
+	return nil
+}
+
+// validateView normalizes the view and checks that it can be run
+func (s *SavedViewService) validateView(userID uint, view *models.SavedView) error {
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" || len(view.Name) > 100 {
+		return InvalidField("invalid_name", "name", "invalid view name. Must be 1-100 characters")
+	}
+
+	if view.Pagination == "" {
+		view.Pagination = "offset"
+	}
+	if view.Limit == 0 {
+		view.Limit = 10
+	}
+	if view.Limit < 1 || view.Limit > 50 {
+		return InvalidField("invalid_limit", "limit", "invalid limit. Must be between 1 and 50")
+	}
+
+	if view.WorkspaceID != nil {
+		role, err := getWorkspaceRole(s.db, *view.WorkspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if role == "" {
+			return ErrWorkspaceNotFound
+		}
+	}
+
+	params := view.Params()
+	if err := ValidateTodoFilters(params); err != nil {
+		return err
+	}
+	if _, err := ParseTodoSort(params); err != nil {
+		return err
+	}
+	if view.Query != "" {
+		if _, err := applyTodoQuery(s.db, view.Query, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
+)
+
+// recordingTodos is a todo repository that records the parameters of GetTodos
+type recordingTodos struct {
+	repository.TodoRepository
+	userID uint
+	params models.PaginationParams
+}
+
+func (r *recordingTodos) GetTodos(userID uint, params models.PaginationParams) ([]models.Todo, *models.Pagination, error) {
+	r.userID = userID
+	r.params = params
+	return []models.Todo{{ID: 1}}, &models.Pagination{}, nil
+}
+
+func TestGetViewTodosUsesTheRepository(t *testing.T) {
+	todos := &recordingTodos{}
+	// Built-in views are not stored, so no database is needed
+	s := NewSavedViewService(nil, todos)
+
+	got, _, err := s.GetViewTodos(7, "overdue", models.PaginationParams{Page: 2, Limit: 5, IncludeTotal: true})
+	if err != nil {
+		t.Fatalf("GetViewTodos() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("GetViewTodos() = %+v, want the todos of the repository", got)
+	}
+
+	want := models.PaginationParams{Page: 2, Limit: 5, IncludeTotal: true, Query: "is:overdue", Sort: "due_date,-priority", Pagination: "offset"}
+	p := todos.params
+	if todos.userID != 7 || p.Page != want.Page || p.Limit != want.Limit || p.IncludeTotal != want.IncludeTotal ||
+		p.Query != want.Query || p.Sort != want.Sort || p.Pagination != want.Pagination {
+		t.Errorf("GetTodos(%d, %+v), want GetTodos(7, %+v)", todos.userID, p, want)
+	}
+}
+
+func TestFilterErrorsNameTheParameterOnEngine(t *testing.T) {
+	userID := newTestUser(t)
+
+	_, err := NewSavedViewService(testDB, NewTodoService(testDB)).CreateView(userID, models.CreateSavedViewRequest{
+		Name:    "Broken",
+		Filters: &models.TodoFilters{TagMode: "none"},
+	})
+	if !hasFieldError(err, "tag_mode") {
+		t.Errorf("CreateView() error = %v, want an invalid tag_mode", err)
+	}
+
+	_, _, err = NewTodoService(testDB).BulkUpdate(userID, models.BulkTodoRequest{
+		Filter: &models.BulkTodoFilter{TodoFilters: models.TodoFilters{Priorities: []models.Priority{"urgent"}}},
+		Action: models.BulkActionComplete,
+	})
+	if !hasFieldError(err, "priority") {
+		t.Errorf("BulkUpdate() error = %v, want an invalid priority", err)
+	}
+}
+
+// hasFieldError reports whether err is an invalid_filter error of the field
+func hasFieldError(err error, field string) bool {
+	var serr *Error
+	return errors.As(err, &serr) && serr.Code == "invalid_filter" && len(serr.Fields) == 1 && serr.Fields[0].Field == field
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+var (
+	errTagNotFound    = NotFound("tag_not_found", "tag not found")
+	errTagNameTaken   = Conflict("tag_name_taken", "tag name already exists")
+	errInvalidTagName = InvalidField("invalid_tag_name", "name", "invalid tag name. Must be 1-50 characters without commas")
+)
+
+type TagService struct {
+	db *gorm.DB
+}
+
+func NewTagService(db *gorm.DB) *TagService {
+	return &TagService{
+		db: db,
+	}
+}
+
+// Create Tag
+func (s *TagService) CreateTag(userID uint, req models.CreateTagRequest) (*models.Tag, error) {
+	name := models.NormalizeTagName(req.Name)
+	if !models.ValidateTagName(name) {
+		return nil, errInvalidTagName
+	}
+
+	color := req.Color
+	if color == "" {
+		color = "#6B7280"
+	}
+
+	// Default to the user's personal workspace
+	var workspaceID uint
+	if req.WorkspaceID != nil {
+		workspaceID = *req.WorkspaceID
+	} else {
+		id, err := personalWorkspaceID(s.db, userID)
+		if err != nil {
+			return nil, err
+		}
+		workspaceID = id
+	}
+
+	role, err := getWorkspaceRole(s.db, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrWorkspaceNotFound
+	}
+	if !role.CanWrite() {
+		return nil, ErrInsufficientPermissions
+	}
+
+	tag := models.Tag{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Color:       color,
+	}
+
+	if err := s.db.Create(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errTagNameTaken
+		}
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// Get Tag by ID
+func (s *TagService) GetTagByID(userID, id uint) (*models.Tag, error) {
+	var tag models.Tag
+
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// Get All Tags
+func (s *TagService) GetAllTags(userID, workspaceID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+
+	query := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID))
+	if workspaceID != 0 {
+		query = query.Where("workspace_id = ?", workspaceID)
+	}
+
+	if err := query.Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Update Tag
+func (s *TagService) UpdateTag(userID, id uint, req models.UpdateTagRequest) (*models.Tag, error) {
+	tag, err := s.GetTagByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireWorkspaceWrite(s.db, tag.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := models.NormalizeTagName(*req.Name)
+		if !models.ValidateTagName(name) {
+			return nil, errInvalidTagName
+		}
+		tag.Name = name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+
+	if err := s.db.Save(tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errTagNameTaken
+		}
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// Delete Tag. The tag is detached from every todo that uses it.
+func (s *TagService) DeleteTag(userID, id uint) error {
+	tag, err := s.GetTagByID(userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requireWorkspaceWrite(s.db, tag.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&models.TodoTag{}).Error; err != nil {
+			return fmt.Errorf("failed to detach tag: %w", err)
+		}
+		if err := tx.Delete(tag).Error; err != nil {
+			return fmt.Errorf("failed to delete tag: %w", err)
+		}
+		return nil
+	})
+}
+
+// findWorkspaceTags loads the tags with the given IDs, which must all belong to the workspace
+func findWorkspaceTags(db *gorm.DB, workspaceID uint, ids []uint) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	if len(ids) == 0 {
+		return tags, nil
+	}
+
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+
+	if err := db.Where("workspace_id = ? AND id IN ?", workspaceID, ids).Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to validate tags: %w", err)
+	}
+	if len(tags) != len(unique) {
+		return nil, InvalidField("tag_not_found", "tag_ids", "tag not found")
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+)
+
+// maxBulkTodos limits how many todos one bulk operation may change
+const maxBulkTodos = 500
+
+// ErrBulkRolledBack aborts the transaction of an all-or-nothing bulk
+// operation. Nothing was changed, so the request fails as a whole.
+var ErrBulkRolledBack = Conflict("bulk_rolled_back", "bulk operation rolled back. No todo was changed")
+
+var errTooManyTodos = Validation("too_many_todos", fmt.Sprintf("too many todos. A bulk operation can change at most %d todos", maxBulkTodos))
+
+// BulkUpdate applies one action to many todos in a single transaction. Every
+// todo gets its own result. Without AllOrNothing a failing todo is rolled back
+// on its own (with a savepoint) and the others are still changed; with
+// AllOrNothing the first failure rolls back everything.
+func (s *TodoService) BulkUpdate(userID uint, req models.BulkTodoRequest) ([]models.BulkTodoResult, bool, error) {
+	category, err := s.validateBulkRequest(userID, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ids, err := s.bulkTodoIDs(userID, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var todos []models.Todo
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).Where("id IN ?", ids).Find(&todos).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to get todos: %w", err)
+	}
+	found := make(map[uint]*models.Todo, len(todos))
+	for i := range todos {
+		found[todos[i].ID] = &todos[i]
+	}
+
+	// Todos that cannot be changed fail before the transaction starts
+	results := make([]models.BulkTodoResult, len(ids))
+	roles := make(map[uint]models.WorkspaceRole)
+	for i, id := range ids {
+		results[i].ID = id
+
+		todo, ok := found[id]
+		if !ok {
+			results[i].Status = models.BulkStatusFailed
+			results[i].Error = "todo not found"
+			continue
+		}
+		if _, ok := roles[todo.WorkspaceID]; !ok {
+			role, err := getWorkspaceRole(s.db, todo.WorkspaceID, userID)
+			if err != nil {
+				return nil, false, err
+			}
+			roles[todo.WorkspaceID] = role
+		}
+		if !roles[todo.WorkspaceID].CanWrite() {
+			results[i].Status = models.BulkStatusFailed
+			results[i].Error = "insufficient workspace permissions"
+		}
+	}
+	rolledBack := false
+
+	err = realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if results[i].Status == "" {
+				todo := found[id]
+				apply := func(tx *gorm.DB) error {
+					changed, err := applyBulkAction(tx, todo, req, category)
+					if err != nil {
+						return err
+					}
+					results[i].Status = models.BulkStatusUnchanged
+					if changed {
+						results[i].Status = models.BulkStatusSucceeded
+					}
+					return nil
+				}
+
+				var err error
+				if req.AllOrNothing {
+					err = apply(tx)
+				} else {
+					err = realtime.Transaction(tx, apply)
+				}
+				if err != nil {
+					results[i].Status = models.BulkStatusFailed
+					results[i].Error = err.Error()
+				}
+			}
+
+			if req.AllOrNothing && results[i].Status == models.BulkStatusFailed {
+				return ErrBulkRolledBack
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, ErrBulkRolledBack) {
+		rolledBack = true
+		for i := range results {
+			// Todos after the failure were not attempted and have no status yet
+			if results[i].Status != models.BulkStatusFailed {
+				results[i].Status = models.BulkStatusRolledBack
+			}
+		}
+	} else if err != nil {
+		return nil, false, fmt.Errorf("failed to apply bulk action: %w", err)
+	}
+
+	return results, rolledBack, nil
+}
+
+// validateBulkRequest checks the action and its parameters and returns the
+// target category of a move
+func (s *TodoService) validateBulkRequest(userID uint, req models.BulkTodoRequest) (*models.Category, error) {
+	switch req.Action {
+	case models.BulkActionComplete, models.BulkActionUncomplete, models.BulkActionDelete, models.BulkActionSetDueDate:
+	case models.BulkActionMove:
+		if req.CategoryID == nil {
+			return nil, InvalidField("category_id_required", "category_id", "category_id is required for move")
+		}
+		var category models.Category
+		if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&category, *req.CategoryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrCategoryNotFound
+			}
+			return nil, fmt.Errorf("failed to validate category: %w", err)
+		}
+		return &category, nil
+	case models.BulkActionSetPriority:
+		if req.Priority == nil || !models.ValidatePriority(*req.Priority) {
+			return nil, ErrInvalidPriority
+		}
+	default:
+		return nil, InvalidField("invalid_action", "action", "invalid bulk action. Must be 'complete', 'uncomplete', 'delete', 'move', 'set_priority', or 'set_due_date'")
+	}
+	return nil, nil
+}
+
+// bulkTodoIDs returns the deduplicated IDs of the request or the IDs of the
+// todos matched by its filter
+func (s *TodoService) bulkTodoIDs(userID uint, req models.BulkTodoRequest) ([]uint, error) {
+	if len(req.IDs) > 0 && req.Filter != nil {
+		return nil, Validation("ids_and_filter_exclusive", "ids and filter are mutually exclusive")
+	}
+	if len(req.IDs) == 0 && req.Filter == nil {
+		return nil, Validation("ids_or_filter_required", "either ids or filter is required")
+	}
+
+	if req.Filter == nil {
+		var ids []uint
+		seen := make(map[uint]bool, len(req.IDs))
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > maxBulkTodos {
+			return nil, errTooManyTodos
+		}
+		return ids, nil
+	}
+
+	// A filter must narrow the todos down; it never selects everything
+	filter := req.Filter
+	if filter.Search == "" && filter.Query == "" && filter.TodoFilters.Empty() {
+		return nil, InvalidField("invalid_filter", "filter", "filter requires search, q or another filter")
+	}
+
+	params := filter.TodoFilters.Params()
+	params.Search = filter.Search
+	if err := ValidateTodoFilters(params); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Model(&models.Todo{}).
+		Where("todos.workspace_id IN (?)", memberWorkspaceIDs(s.db, userID))
+	if filter.WorkspaceID != 0 {
+		query = query.Where("todos.workspace_id = ?", filter.WorkspaceID)
+	}
+	query = applyTodoFilters(query, params)
+	if filter.Query != "" {
+		var err error
+		if query, err = applyTodoQuery(query, filter.Query, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	var ids []uint
+	if err := query.Order("todos.id ASC").Limit(maxBulkTodos+1).Pluck("todos.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+	if len(ids) > maxBulkTodos {
+		return nil, errTooManyTodos
+	}
+	return ids, nil
+}
+
+// applyBulkAction changes one todo like the single todo endpoints do and
+// reports whether anything changed
+func applyBulkAction(tx *gorm.DB, todo *models.Todo, req models.BulkTodoRequest, category *models.Category) (bool, error) {
+	switch req.Action {
+	case models.BulkActionComplete, models.BulkActionUncomplete:
+		completed := req.Action == models.BulkActionComplete
+		if todo.Completed == completed {
+			return false, nil
+		}
+		todo.Completed = completed
+		return true, saveCompletion(tx, todo)
+
+	case models.BulkActionDelete:
+		return true, deleteTodo(tx, todo)
+
+	case models.BulkActionMove:
+		if category.WorkspaceID != todo.WorkspaceID {
+			return false, ErrCategoryNotFound
+		}
+		if todo.CategoryID == category.ID {
+			return false, nil
+		}
+		todo.CategoryID = category.ID
+
+	case models.BulkActionSetPriority:
+		if todo.Priority == *req.Priority {
+			return false, nil
+		}
+		todo.Priority = *req.Priority
+
+	case models.BulkActionSetDueDate:
+		// Recurring todos need a due date to compute the next occurrence
+		if req.DueDate == nil && todo.RecurrenceRule != "" {
+			return false, errRecurrenceDueDateRequired
+		}
+		if sameDueDate(todo.DueDate, req.DueDate) {
+			return false, nil
+		}
+		todo.DueDate = req.DueDate
+		// Moving the first occurrence moves the whole series
+		if todo.RecurrenceIndex == 1 && todo.DueDate != nil {
+			start := *todo.DueDate
+			todo.RecurrenceStart = &start
+		}
+	}
+
+	if err := tx.Save(todo).Error; err != nil {
+		return false, fmt.Errorf("failed to update todo: %w", err)
+	}
+	if req.Action == models.BulkActionSetDueDate {
+		// Reminders are relative to the due date, so removing it removes them
+		var err error
+		if todo.DueDate == nil {
+			err = replaceReminders(tx, todo, nil)
+		} else {
+			err = rescheduleReminders(tx, todo)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, emitTodoEvent(tx, models.EventTodoUpdated, todo)
+}
+
+func sameDueDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+// ErrInvalidCursor is returned when a cursor token cannot be decoded or does
+// not belong to the requested sort order
+var ErrInvalidCursor = InvalidField("invalid_cursor", "cursor", "invalid cursor")
+
+// todoCursor is the decoded form of the opaque cursor token. It stores the
+// sort spec it was created for and the sort key values of the last row.
+type todoCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// isCursorMode reports whether keyset pagination was requested
+func isCursorMode(params models.PaginationParams) bool {
+	return params.Cursor != "" || params.Pagination == "cursor"
+}
+
+// sortSpecString returns the canonical string form of a parsed sort spec
+func sortSpecString(sorts []TodoSort) string {
+	parts := make([]string, len(sorts))
+	for i, sort := range sorts {
+		parts[i] = sort.Field
+		if sort.Desc {
+			parts[i] = "-" + sort.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// priorityRank mirrors the CASE expression used for sorting by priority
+func priorityRank(p models.Priority) int {
+	switch p {
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityMedium:
+		return 2
+	case models.PriorityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// todoSortValue returns the value of a sortable field for the given todo
+func todoSortValue(todo models.Todo, field string) interface{} {
+	switch field {
+	case "id":
+		return todo.ID
+	case "title":
+		return todo.Title
+	case "priority":
+		return priorityRank(todo.Priority)
+	case "completed":
+		return todo.Completed
+	case "due_date":
+		return todo.DueDate
+	case "created_at":
+		return todo.CreatedAt
+	case "updated_at":
+		return todo.UpdatedAt
+	default:
+		return nil
+	}
+}
+
+// encodeTodoCursor builds the opaque token pointing after the given todo
+func encodeTodoCursor(todo models.Todo, sorts []TodoSort) (string, error) {
+	cursor := todoCursor{Sort: sortSpecString(sorts)}
+	for _, sort := range sorts {
+		value, err := json.Marshal(todoSortValue(todo, sort.Field))
+		if err != nil {
+			return "", fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// decodeTodoCursor parses a cursor token into typed values for each sort field
+func decodeTodoCursor(token string, sorts []TodoSort) ([]interface{}, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor todoCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cursor.Sort != sortSpecString(sorts) || len(cursor.Values) != len(sorts) {
+		return nil, fmt.Errorf("%w: cursor does not match the requested sort order", ErrInvalidCursor)
+	}
+
+	values := make([]interface{}, len(sorts))
+	for i, sort := range sorts {
+		var err error
+		switch sort.Field {
+		case "id":
+			var v uint
+			err = json.Unmarshal(cursor.Values[i], &v)
+			values[i] = v
+		case "title":
+			var v string
+			err = json.Unmarshal(cursor.Values[i], &v)
+			values[i] = v
+		case "priority":
+			var v int
+			err = json.Unmarshal(cursor.Values[i], &v)
+			values[i] = v
+		case "completed":
+			var v bool
+			err = json.Unmarshal(cursor.Values[i], &v)
+			values[i] = v
+		case "due_date":
+			var v *time.Time
+			err = json.Unmarshal(cursor.Values[i], &v)
+			if v != nil {
+				values[i] = *v
+			}
+		case "created_at", "updated_at":
+			var v time.Time
+			err = json.Unmarshal(cursor.Values[i], &v)
+			values[i] = v
+		}
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return values, nil
+}
+
+// applyTodoCursor restricts the query to rows that come strictly after the
+// cursor position in the given sort order. For sorts (a, b, c) this expands to
+// (a after) OR (a = AND b after) OR (a = AND b = AND c after).
+func applyTodoCursor(query *gorm.DB, sorts []TodoSort, values []interface{}) *gorm.DB {
+	var branches []string
+	var args []interface{}
+
+	var equalConds []string
+	var equalArgs []interface{}
+
+	for i, sort := range sorts {
+		column := todoSortColumns[sort.Field]
+		value := values[i]
+
+		afterCond, afterArgs := cursorAfterCondition(column, sort.Desc, value)
+		if afterCond != "" {
+			branch := append(append([]string{}, equalConds...), afterCond)
+			branches = append(branches, "("+strings.Join(branch, " AND ")+")")
+			args = append(args, equalArgs...)
+			args = append(args, afterArgs...)
+		}
+
+		if value == nil {
+			equalConds = append(equalConds, column.expr+" IS NULL")
+		} else {
+			equalConds = append(equalConds, column.expr+" = ?")
+			equalArgs = append(equalArgs, value)
+		}
+	}
+
+	if len(branches) == 0 {
+		return query.Where("1 = 0")
+	}
+
+	return query.Where("("+strings.Join(branches, " OR ")+")", args...)
+}
+
+// cursorAfterCondition returns the condition matching values that sort after
+// value for a single column. NULLs always sort last, so nothing comes after a
+// NULL and every NULL comes after a non-NULL value.
+func cursorAfterCondition(column sortColumn, desc bool, value interface{}) (string, []interface{}) {
+	if value == nil {
+		return "", nil
+	}
+
+	op := ">"
+	if desc {
+		op = "<"
+	}
+
+	cond := column.expr + " " + op + " ?"
+	if column.nullsLast {
+		cond = "(" + cond + " OR " + column.expr + " IS NULL)"
+	}
+
+	return cond, []interface{}{value}
+}
+
+// getTodosByCursor fetches one page of todos using keyset pagination. The
+// total count is only computed when explicitly requested.
+func getTodosByCursor(query *gorm.DB, sorts []TodoSort, limit int, params models.PaginationParams) ([]models.Todo, *models.Pagination, error) {
+	var todos []models.Todo
+
+	pagination := &models.Pagination{
+		PerPage: limit,
+	}
+
+	if params.IncludeTotal {
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to count todos: %w", err)
+		}
+
+		totalPages := int(total) / limit
+		if int(total)%limit != 0 {
+			totalPages++
+		}
+		pagination.Total = total
+		pagination.TotalPages = totalPages
+	}
+
+	if params.Cursor != "" {
+		values, err := decodeTodoCursor(params.Cursor, sorts)
+		if err != nil {
+			return nil, nil, err
+		}
+		query = applyTodoCursor(query, sorts, values)
+	}
+
+	if params.Search != "" {
+		query = selectTodoSearch(query, params.Search)
+	}
+	query = applyTodoSort(query, sorts)
+
+	// Fetch one extra row to know whether there is a next page
+	if err := query.Limit(limit + 1).Find(&todos).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+	highlightSearch(query, todos, params.Search)
+
+	if len(todos) > limit {
+		todos = todos[:limit]
+
+		nextCursor, err := encodeTodoCursor(todos[len(todos)-1], sorts)
+		if err != nil {
+			return nil, nil, err
+		}
+		pagination.NextCursor = nextCursor
+		pagination.HasMore = true
+	}
+
+	return todos, pagination, nil
+}
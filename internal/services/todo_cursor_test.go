@@ -0,0 +1,214 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+func TestTodoCursorRoundTrip(t *testing.T) {
+	due := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+	tests := []struct {
+		name  string
+		todo  models.Todo
+		sorts []TodoSort
+		want  []interface{}
+	}{
+		{
+			name:  "default sort",
+			todo:  models.Todo{ID: 7, CreatedAt: created},
+			sorts: []TodoSort{{Field: "created_at", Desc: true}, {Field: "id"}},
+			want:  []interface{}{created, uint(7)},
+		},
+		{
+			name:  "priority, title and completed",
+			todo:  models.Todo{ID: 3, Priority: models.PriorityMedium, Title: "Buy milk", Completed: true},
+			sorts: []TodoSort{{Field: "priority", Desc: true}, {Field: "title"}, {Field: "completed"}, {Field: "id"}},
+			want:  []interface{}{2, "Buy milk", true, uint(3)},
+		},
+		{
+			name:  "due date",
+			todo:  models.Todo{ID: 4, DueDate: &due},
+			sorts: []TodoSort{{Field: "due_date"}, {Field: "id"}},
+			want:  []interface{}{due, uint(4)},
+		},
+		{
+			name:  "missing due date",
+			todo:  models.Todo{ID: 5},
+			sorts: []TodoSort{{Field: "due_date"}, {Field: "id"}},
+			want:  []interface{}{nil, uint(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := encodeTodoCursor(tt.todo, tt.sorts)
+			if err != nil {
+				t.Fatalf("encodeTodoCursor() error = %v", err)
+			}
+
+			got, err := decodeTodoCursor(token, tt.sorts)
+			if err != nil {
+				t.Fatalf("decodeTodoCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeTodoCursor() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTodoCursorRejectsInvalidTokens(t *testing.T) {
+	sorts := []TodoSort{{Field: "created_at", Desc: true}, {Field: "id"}}
+	valid, err := encodeTodoCursor(models.Todo{ID: 1, CreatedAt: time.Now()}, sorts)
+	if err != nil {
+		t.Fatalf("encodeTodoCursor() error = %v", err)
+	}
+
+	encode := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		sorts []TodoSort
+	}{
+		{name: "not base64", token: "not a cursor!", sorts: sorts},
+		{name: "not json", token: encode("{"), sorts: sorts},
+		{name: "other sort order", token: valid, sorts: []TodoSort{{Field: "created_at"}, {Field: "id"}}},
+		{name: "other sort fields", token: valid, sorts: []TodoSort{{Field: "title"}, {Field: "id"}}},
+		{name: "missing values", token: encode(`{"s":"-created_at,id","v":["2024-01-01T00:00:00Z"]}`), sorts: sorts},
+		{name: "wrong value type", token: encode(`{"s":"-created_at,id","v":["2024-01-01T00:00:00Z","seven"]}`), sorts: sorts},
+		{name: "invalid time", token: encode(`{"s":"-created_at,id","v":["yesterday",7]}`), sorts: sorts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeTodoCursor(tt.token, tt.sorts)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeTodoCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestApplyTodoCursor(t *testing.T) {
+	due := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		sorts    []TodoSort
+		values   []interface{}
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:     "single column ascending",
+			sorts:    []TodoSort{{Field: "id"}},
+			values:   []interface{}{uint(3)},
+			wantSQL:  `((todos.id > $1))`,
+			wantVars: []interface{}{uint(3)},
+		},
+		{
+			name:     "descending with tie-breaker",
+			sorts:    []TodoSort{{Field: "created_at", Desc: true}, {Field: "id"}},
+			values:   []interface{}{due, uint(3)},
+			wantSQL:  `((todos.created_at < $1) OR (todos.created_at = $2 AND todos.id > $3))`,
+			wantVars: []interface{}{due, due, uint(3)},
+		},
+		{
+			// NULL due dates sort last, so they come after every date
+			name:     "due date includes the NULLs after it",
+			sorts:    []TodoSort{{Field: "due_date"}, {Field: "id"}},
+			values:   []interface{}{due, uint(3)},
+			wantSQL:  `(((todos.due_date > $1 OR todos.due_date IS NULL)) OR (todos.due_date = $2 AND todos.id > $3))`,
+			wantVars: []interface{}{due, due, uint(3)},
+		},
+		{
+			name:     "descending due date includes the NULLs after it",
+			sorts:    []TodoSort{{Field: "due_date", Desc: true}, {Field: "id"}},
+			values:   []interface{}{due, uint(3)},
+			wantSQL:  `(((todos.due_date < $1 OR todos.due_date IS NULL)) OR (todos.due_date = $2 AND todos.id > $3))`,
+			wantVars: []interface{}{due, due, uint(3)},
+		},
+		{
+			// Nothing sorts after a NULL, so only the tie-breaker remains
+			name:     "NULL due date is broken by id",
+			sorts:    []TodoSort{{Field: "due_date"}, {Field: "id"}},
+			values:   []interface{}{nil, uint(3)},
+			wantSQL:  `((todos.due_date IS NULL AND todos.id > $1))`,
+			wantVars: []interface{}{uint(3)},
+		},
+		{
+			name:     "NULL due date between other columns",
+			sorts:    []TodoSort{{Field: "priority", Desc: true}, {Field: "due_date"}, {Field: "id", Desc: true}},
+			values:   []interface{}{3, nil, uint(9)},
+			wantSQL:  `((CASE todos.priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END < $1) OR (CASE todos.priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END = $2 AND todos.due_date IS NULL AND todos.id < $3))`,
+			wantVars: []interface{}{3, 3, uint(9)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Rows without a model skip the soft delete condition
+			var rows []map[string]interface{}
+			db := applyTodoCursor(dryRunDB(t).Table("todos"), tt.sorts, tt.values).Find(&rows)
+
+			want := `SELECT * FROM "todos" WHERE ` + tt.wantSQL
+			if got := dryRunSQL(t, db); got != want {
+				t.Errorf("applyTodoCursor() SQL =\n%s\nwant\n%s", got, want)
+			}
+			if !reflect.DeepEqual(db.Statement.Vars, tt.wantVars) {
+				t.Errorf("applyTodoCursor() vars = %#v, want %#v", db.Statement.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestTodoCursorOnEngine(t *testing.T) {
+	userID := newListTodos(t)
+	s := NewTodoService(testDB)
+
+	sorts := []string{
+		"id", "-id", "title", "-title", "priority", "-priority", "completed", "-completed",
+		"due_date", "-due_date", "created_at", "-created_at", "updated_at", "-updated_at",
+		"-priority,due_date", "completed,-due_date,-id",
+	}
+
+	for _, sort := range sorts {
+		t.Run(sort, func(t *testing.T) {
+			all, _, err := s.GetTodos(userID, models.PaginationParams{Sort: sort})
+			if err != nil {
+				t.Fatalf("GetTodos() error = %v", err)
+			}
+			want := todoTitles(all)
+
+			// Pages of two cross the ties in priority and completed and the
+			// NULL due dates
+			var got []string
+			params := models.PaginationParams{Pagination: "cursor", Limit: 2, Sort: sort}
+			for page := 0; page < len(want); page++ {
+				todos, pagination, err := s.GetTodos(userID, params)
+				if err != nil {
+					t.Fatalf("GetTodos() page %d error = %v", page, err)
+				}
+				got = append(got, todoTitles(todos)...)
+				if !pagination.HasMore {
+					break
+				}
+				params.Cursor = pagination.NextCursor
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("cursor pages = %q, want %q", got, want)
+			}
+		})
+	}
+}
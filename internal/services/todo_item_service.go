@@ -0,0 +1,298 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+)
+
+var errItemIDsMismatch = InvalidField("invalid_item_ids", "item_ids", "item_ids must contain every item of the todo exactly once")
+
+type TodoItemService struct {
+	db *gorm.DB
+}
+
+func NewTodoItemService(db *gorm.DB) *TodoItemService {
+	return &TodoItemService{
+		db: db,
+	}
+}
+
+// getTodoForUser loads a todo the user can see, optionally checking write access
+func getTodoForUser(db *gorm.DB, userID, todoID uint, write bool) (*models.Todo, error) {
+	var todo models.Todo
+
+	if err := db.Where("workspace_id IN (?)", memberWorkspaceIDs(db, userID)).First(&todo, todoID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("failed to get todo: %w", err)
+	}
+
+	if write {
+		if err := requireWorkspaceWrite(db, todo.WorkspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &todo, nil
+}
+
+// syncAutoComplete sets the completion of an auto-completing todo from its
+// checklist: completed when every item is checked, open otherwise. Todos
+// without items are left untouched. The todo is not saved.
+func syncAutoComplete(db *gorm.DB, todo *models.Todo) error {
+	if !todo.AutoComplete {
+		return nil
+	}
+
+	var total, completed int64
+	if err := db.Model(&models.TodoItem{}).Where("todo_id = ?", todo.ID).Count(&total).Error; err != nil {
+		return fmt.Errorf("failed to count todo items: %w", err)
+	}
+	if total == 0 {
+		return nil
+	}
+	if err := db.Model(&models.TodoItem{}).Where("todo_id = ? AND completed = ?", todo.ID, true).Count(&completed).Error; err != nil {
+		return fmt.Errorf("failed to count todo items: %w", err)
+	}
+
+	todo.Completed = completed == total
+	return nil
+}
+
+// afterItemChange re-evaluates auto-completion of the todo after its checklist changed
+func afterItemChange(db *gorm.DB, todo *models.Todo) error {
+	if !todo.AutoComplete {
+		return nil
+	}
+
+	completed := todo.Completed
+	if err := syncAutoComplete(db, todo); err != nil {
+		return err
+	}
+	if todo.Completed == completed {
+		return nil
+	}
+
+	if err := db.Model(todo).Update("completed", todo.Completed).Error; err != nil {
+		return fmt.Errorf("failed to update todo completion: %w", err)
+	}
+
+	if todo.Completed {
+		if err := spawnNextOccurrence(db, todo); err != nil {
+			return err
+		}
+		return emitTodoEvent(db, models.EventTodoCompleted, todo)
+	}
+	return emitTodoEvent(db, models.EventTodoUpdated, todo)
+}
+
+// getItem loads a checklist item belonging to the todo
+func getItem(db *gorm.DB, todoID, itemID uint) (*models.TodoItem, error) {
+	var item models.TodoItem
+
+	if err := db.Where("todo_id = ?", todoID).First(&item, itemID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NotFound("todo_item_not_found", "todo item not found")
+		}
+		return nil, fmt.Errorf("failed to get todo item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// Get Todo Items
+func (s *TodoItemService) GetItems(userID, todoID uint) ([]models.TodoItem, error) {
+	if _, err := getTodoForUser(s.db, userID, todoID, false); err != nil {
+		return nil, err
+	}
+
+	var items []models.TodoItem
+	if err := s.db.Where("todo_id = ?", todoID).Order("position ASC, id ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get todo items: %w", err)
+	}
+
+	return items, nil
+}
+
+// Create Todo Item. New items are appended to the end of the checklist.
+func (s *TodoItemService) CreateItem(userID, todoID uint, req models.CreateTodoItemRequest) (*models.TodoItem, error) {
+	var item models.TodoItem
+
+	err := realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		todo, err := getTodoForUser(tx, userID, todoID, true)
+		if err != nil {
+			return err
+		}
+
+		var maxPosition *int
+		if err := tx.Model(&models.TodoItem{}).Where("todo_id = ?", todoID).Select("MAX(position)").Scan(&maxPosition).Error; err != nil {
+			return fmt.Errorf("failed to get todo item position: %w", err)
+		}
+
+		item = models.TodoItem{
+			TodoID:    todoID,
+			Title:     req.Title,
+			Completed: req.Completed,
+		}
+		if maxPosition != nil {
+			item.Position = *maxPosition + 1
+		}
+
+		if err := tx.Create(&item).Error; err != nil {
+			return fmt.Errorf("failed to create todo item: %w", err)
+		}
+
+		return afterItemChange(tx, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// Update Todo Item
+func (s *TodoItemService) UpdateItem(userID, todoID, itemID uint, req models.UpdateTodoItemRequest) (*models.TodoItem, error) {
+	var item *models.TodoItem
+
+	err := realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		todo, err := getTodoForUser(tx, userID, todoID, true)
+		if err != nil {
+			return err
+		}
+
+		item, err = getItem(tx, todoID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if req.Title != nil {
+			if *req.Title == "" {
+				return InvalidField("title_required", "title", "title is required")
+			}
+			item.Title = *req.Title
+		}
+		if req.Completed != nil {
+			item.Completed = *req.Completed
+		}
+
+		if err := tx.Save(item).Error; err != nil {
+			return fmt.Errorf("failed to update todo item: %w", err)
+		}
+
+		return afterItemChange(tx, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Toggle Todo Item Complete
+func (s *TodoItemService) ToggleItem(userID, todoID, itemID uint) (*models.TodoItem, error) {
+	var item *models.TodoItem
+
+	err := realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		todo, err := getTodoForUser(tx, userID, todoID, true)
+		if err != nil {
+			return err
+		}
+
+		item, err = getItem(tx, todoID, itemID)
+		if err != nil {
+			return err
+		}
+
+		item.Completed = !item.Completed
+		if err := tx.Model(item).Update("completed", item.Completed).Error; err != nil {
+			return fmt.Errorf("failed to toggle todo item: %w", err)
+		}
+
+		return afterItemChange(tx, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Delete Todo Item
+func (s *TodoItemService) DeleteItem(userID, todoID, itemID uint) error {
+	return realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		todo, err := getTodoForUser(tx, userID, todoID, true)
+		if err != nil {
+			return err
+		}
+
+		item, err := getItem(tx, todoID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(item).Error; err != nil {
+			return fmt.Errorf("failed to delete todo item: %w", err)
+		}
+
+		return afterItemChange(tx, todo)
+	})
+}
+
+// Reorder Todo Items. The request must list every item of the todo exactly once.
+func (s *TodoItemService) ReorderItems(userID, todoID uint, req models.ReorderTodoItemsRequest) ([]models.TodoItem, error) {
+	var items []models.TodoItem
+
+	err := realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		if _, err := getTodoForUser(tx, userID, todoID, true); err != nil {
+			return err
+		}
+
+		if err := tx.Where("todo_id = ?", todoID).Find(&items).Error; err != nil {
+			return fmt.Errorf("failed to get todo items: %w", err)
+		}
+
+		positions := make(map[uint]int, len(req.ItemIDs))
+		for i, id := range req.ItemIDs {
+			if _, ok := positions[id]; ok {
+				return InvalidField("invalid_item_ids", "item_ids", "item_ids must not contain duplicates")
+			}
+			positions[id] = i
+		}
+		if len(positions) != len(items) {
+			return errItemIDsMismatch
+		}
+
+		for i := range items {
+			position, ok := positions[items[i].ID]
+			if !ok {
+				return errItemIDsMismatch
+			}
+			if items[i].Position == position {
+				continue
+			}
+			items[i].Position = position
+			if err := tx.Model(&items[i]).Update("position", position).Error; err != nil {
+				return fmt.Errorf("failed to reorder todo items: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]models.TodoItem, len(items))
+	for _, item := range items {
+		ordered[item.Position] = item
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+// ErrInvalidQuery is returned when the q filter cannot be parsed
+var ErrInvalidQuery = InvalidField("invalid_query", "q", "invalid query")
+
+// maxQueryLength limits the size of the q filter
+const maxQueryLength = 500
+
+// QueryError reports a problem in the q filter. Position is the 1-based
+// character position where the problem starts.
+type QueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", ErrInvalidQuery, e.Position, e.Message)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrInvalidQuery
+}
+
+// queryFields lists the fields of the query language
+var queryFields = []string{"priority", "category", "tag", "due", "created", "is", "has"}
+
+// queryTerm is one whitespace separated part of the q filter, either a
+// field:value[,value...] filter or a free text word or "quoted phrase"
+type queryTerm struct {
+	pos     int
+	negated bool
+	field   string
+	values  []queryValue
+}
+
+type queryValue struct {
+	pos  int
+	text string
+}
+
+// queryCondition is a SQL condition built from one term. Only the fixed
+// fragments below are ever interpolated; values are always bound as args.
+type queryCondition struct {
+	sql  string
+	args []interface{}
+}
+
+// applyTodoQuery parses a filter like
+//
+//	priority:high,medium due:<2026-11-01 category:"Home" is:overdue has:description -is:completed
+//
+// and adds one condition per term. Terms are combined with AND, comma
+// separated values of a field with OR, and a leading "-" negates a term.
+func applyTodoQuery(query *gorm.DB, input string, now time.Time) (*gorm.DB, error) {
+	terms, err := parseTodoQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, term := range terms {
+		cond, err := todoQueryCondition(query, term, now)
+		if err != nil {
+			return nil, err
+		}
+		if term.negated {
+			query = query.Where("NOT ("+cond.sql+")", cond.args...)
+		} else {
+			query = query.Where("("+cond.sql+")", cond.args...)
+		}
+	}
+
+	return query, nil
+}
+
+// parseTodoQuery splits the filter into terms
+func parseTodoQuery(input string) ([]queryTerm, error) {
+	p := &queryParser{input: []rune(input)}
+	if len(p.input) > maxQueryLength {
+		return nil, &QueryError{Position: maxQueryLength + 1, Message: fmt.Sprintf("query is longer than %d characters", maxQueryLength)}
+	}
+
+	var terms []queryTerm
+	for {
+		p.skipSpace()
+		if p.done() {
+			return terms, nil
+		}
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+}
+
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *queryParser) peek() rune {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *queryParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// errorAt returns a QueryError for the 0-based rune offset
+func (p *queryParser) errorAt(offset int, format string, args ...interface{}) error {
+	return &QueryError{Position: offset + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// term parses [-](field:values | word | "phrase")
+func (p *queryParser) term() (queryTerm, error) {
+	term := queryTerm{pos: p.pos}
+
+	if p.peek() == '-' {
+		term.negated = true
+		p.pos++
+		if p.done() || unicode.IsSpace(p.peek()) {
+			return term, p.errorAt(term.pos, "expected a term after '-'")
+		}
+	}
+
+	if p.peek() == '"' {
+		value, err := p.quoted()
+		if err != nil {
+			return term, err
+		}
+		term.values = []queryValue{value}
+		if !p.done() && !unicode.IsSpace(p.peek()) {
+			return term, p.errorAt(p.pos, "expected a space after quoted string")
+		}
+		return term, nil
+	}
+
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.peek()) && p.peek() != ':' {
+		p.pos++
+	}
+	word := string(p.input[start:p.pos])
+
+	if p.peek() != ':' {
+		term.values = []queryValue{{pos: start, text: word}}
+		return term, nil
+	}
+
+	field := strings.ToLower(word)
+	if !isQueryField(field) {
+		return term, p.errorAt(start, "unknown field %q. Allowed fields: %s", word, strings.Join(queryFields, ", "))
+	}
+	term.field = field
+	p.pos++ // ':'
+
+	for {
+		if p.done() || unicode.IsSpace(p.peek()) || p.peek() == ',' {
+			return term, p.errorAt(p.pos, "expected a value for %q", field)
+		}
+
+		var value queryValue
+		if p.peek() == '"' {
+			var err error
+			if value, err = p.quoted(); err != nil {
+				return term, err
+			}
+		} else {
+			valueStart := p.pos
+			for !p.done() && !unicode.IsSpace(p.peek()) && p.peek() != ',' {
+				if p.peek() == '"' {
+					return term, p.errorAt(p.pos, "unexpected '\"' in value")
+				}
+				p.pos++
+			}
+			value = queryValue{pos: valueStart, text: string(p.input[valueStart:p.pos])}
+		}
+		term.values = append(term.values, value)
+
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+
+	if !p.done() && !unicode.IsSpace(p.peek()) {
+		return term, p.errorAt(p.pos, "expected a space or ',' after value")
+	}
+	return term, nil
+}
+
+// quoted parses a "double quoted" string; \" and \\ are escapes
+func (p *queryParser) quoted() (queryValue, error) {
+	start := p.pos
+	p.pos++ // opening quote
+
+	var b strings.Builder
+	for !p.done() {
+		r := p.peek()
+		p.pos++
+		switch r {
+		case '"':
+			if b.Len() == 0 {
+				return queryValue{}, p.errorAt(start, "empty quoted string")
+			}
+			return queryValue{pos: start, text: b.String()}, nil
+		case '\\':
+			if !p.done() && (p.peek() == '"' || p.peek() == '\\') {
+				r = p.peek()
+				p.pos++
+			}
+		}
+		b.WriteRune(r)
+	}
+
+	return queryValue{}, p.errorAt(start, "unterminated quoted string")
+}
+
+func isQueryField(field string) bool {
+	for _, f := range queryFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// todoQueryCondition translates one term into SQL. Conditions never evaluate
+// to NULL, so negating a term also matches todos where the column is empty.
+func todoQueryCondition(query *gorm.DB, term queryTerm, now time.Time) (queryCondition, error) {
+	switch term.field {
+	case "":
+		return textCondition(query, term.values[0]), nil
+	case "priority":
+		var priorities []models.Priority
+		for _, value := range term.values {
+			priority := models.Priority(strings.ToLower(value.text))
+			if !models.ValidatePriority(priority) {
+				return queryCondition{}, valueError(value, "invalid priority %q. Must be 'high', 'medium', or 'low'", value.text)
+			}
+			priorities = append(priorities, priority)
+		}
+		return queryCondition{sql: "todos.priority IN ?", args: []interface{}{priorities}}, nil
+	case "category":
+		var names []string
+		for _, value := range term.values {
+			names = append(names, strings.ToLower(value.text))
+		}
+		return queryCondition{
+			sql:  "todos.category_id IN (SELECT categories.id FROM categories WHERE LOWER(categories.name) IN ? AND categories.deleted_at IS NULL)",
+			args: []interface{}{names},
+		}, nil
+	case "tag":
+		var names []string
+		for _, value := range term.values {
+			name := models.NormalizeTagName(value.text)
+			if name == "" {
+				return queryCondition{}, valueError(value, "invalid tag %q", value.text)
+			}
+			names = append(names, name)
+		}
+		return queryCondition{
+			sql:  "todos.id IN (SELECT todo_tags.todo_id FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.name IN ?)",
+			args: []interface{}{names},
+		}, nil
+	case "due":
+		return anyOf(term.values, func(value queryValue) (queryCondition, error) {
+			return dateCondition("todos.due_date", value, now)
+		})
+	case "created":
+		return anyOf(term.values, func(value queryValue) (queryCondition, error) {
+			return dateCondition("todos.created_at", value, now)
+		})
+	case "is":
+		return anyOf(term.values, func(value queryValue) (queryCondition, error) {
+			return isCondition(value, now)
+		})
+	case "has":
+		return anyOf(term.values, hasCondition)
+	}
+
+	return queryCondition{}, &QueryError{Position: term.pos + 1, Message: fmt.Sprintf("unknown field %q", term.field)}
+}
+
+// anyOf combines the conditions of each value with OR
+func anyOf(values []queryValue, build func(queryValue) (queryCondition, error)) (queryCondition, error) {
+	var parts []string
+	var args []interface{}
+	for _, value := range values {
+		cond, err := build(value)
+		if err != nil {
+			return queryCondition{}, err
+		}
+		parts = append(parts, "("+cond.sql+")")
+		args = append(args, cond.args...)
+	}
+	return queryCondition{sql: strings.Join(parts, " OR "), args: args}, nil
+}
+
+// textCondition matches a word or phrase in the title or description
+func textCondition(query *gorm.DB, value queryValue) queryCondition {
+	if isPostgres(query) {
+		return queryCondition{
+			sql:  "todos.search_vector @@ phraseto_tsquery('" + searchConfig + "', ?)",
+			args: []interface{}{value.text},
+		}
+	}
+
+	return likeCondition(strings.ToLower(value.text))
+}
+
+func isCondition(value queryValue, now time.Time) (queryCondition, error) {
+	switch strings.ToLower(value.text) {
+	case "completed", "done":
+		return queryCondition{sql: "todos.completed = ?", args: []interface{}{true}}, nil
+	case "open", "pending":
+		return queryCondition{sql: "todos.completed = ?", args: []interface{}{false}}, nil
+	case "overdue":
+		return queryCondition{
+			sql:  "todos.completed = ? AND todos.due_date IS NOT NULL AND todos.due_date < ?",
+			args: []interface{}{false, now},
+		}, nil
+	case "recurring":
+		return queryCondition{sql: "COALESCE(todos.recurrence_rule, '') <> ''"}, nil
+	}
+	return queryCondition{}, valueError(value, "invalid value %q for is. Must be 'completed', 'done', 'open', 'pending', 'overdue', or 'recurring'", value.text)
+}
+
+func hasCondition(value queryValue) (queryCondition, error) {
+	switch strings.ToLower(value.text) {
+	case "description":
+		return queryCondition{sql: "COALESCE(todos.description, '') <> ''"}, nil
+	case "due":
+		return queryCondition{sql: "todos.due_date IS NOT NULL"}, nil
+	case "tags":
+		return queryCondition{sql: "EXISTS (SELECT 1 FROM todo_tags WHERE todo_tags.todo_id = todos.id)"}, nil
+	case "items":
+		return queryCondition{sql: "EXISTS (SELECT 1 FROM todo_items WHERE todo_items.todo_id = todos.id)"}, nil
+	case "reminders":
+		return queryCondition{sql: "EXISTS (SELECT 1 FROM reminders WHERE reminders.todo_id = todos.id)"}, nil
+	}
+	return queryCondition{}, valueError(value, "invalid value %q for has. Must be 'description', 'due', 'tags', 'items', or 'reminders'", value.text)
+}
+
+// dateCondition compares a timestamp column with a date (2026-11-01, in
+// UTC), an RFC 3339 timestamp, today/tomorrow/yesterday or a duration
+// relative to now (7d, -2w, 12h). The value may start with <, <=, > or >=.
+// Without an operator a date matches the whole day and a duration the range
+// between now and now + duration.
+func dateCondition(column string, value queryValue, now time.Time) (queryCondition, error) {
+	text := value.text
+	op := ""
+	for _, candidate := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(text, candidate) {
+			op = candidate
+			text = text[len(candidate):]
+			break
+		}
+	}
+	if text == "" {
+		return queryCondition{}, valueError(value, "expected a date after %q", op)
+	}
+
+	start, end, relative, err := parseQueryDate(text, now)
+	if err != nil {
+		return queryCondition{}, &QueryError{Position: value.pos + len(op) + 1, Message: err.Error()}
+	}
+
+	// Days cover [start, end); timestamps and durations are a single instant
+	notNull := column + " IS NOT NULL AND "
+	day := end.After(start)
+	switch {
+	case op == "<":
+		return queryCondition{sql: notNull + column + " < ?", args: []interface{}{start}}, nil
+	case op == "<=" && day:
+		return queryCondition{sql: notNull + column + " < ?", args: []interface{}{end}}, nil
+	case op == "<=":
+		return queryCondition{sql: notNull + column + " <= ?", args: []interface{}{start}}, nil
+	case op == ">" && day:
+		return queryCondition{sql: notNull + column + " >= ?", args: []interface{}{end}}, nil
+	case op == ">":
+		return queryCondition{sql: notNull + column + " > ?", args: []interface{}{start}}, nil
+	case op == ">=":
+		return queryCondition{sql: notNull + column + " >= ?", args: []interface{}{start}}, nil
+	case day:
+		return queryCondition{sql: notNull + column + " >= ? AND " + column + " < ?", args: []interface{}{start, end}}, nil
+	case relative && op == "":
+		from, to := now, start
+		if to.Before(from) {
+			from, to = to, from
+		}
+		return queryCondition{sql: notNull + column + " >= ? AND " + column + " < ?", args: []interface{}{from, to}}, nil
+	}
+	return queryCondition{sql: notNull + column + " = ?", args: []interface{}{start}}, nil
+}
+
+// parseQueryDate returns the range [start, end) of a day, or start == end for
+// a timestamp or a duration relative to now
+func parseQueryDate(text string, now time.Time) (start, end time.Time, relative bool, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch strings.ToLower(text) {
+	case "today":
+		return today, today.AddDate(0, 0, 1), false, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), false, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, false, nil
+	}
+
+	if day, err := time.Parse("2006-01-02", text); err == nil {
+		return day, day.AddDate(0, 0, 1), false, nil
+	}
+	if instant, err := time.Parse(time.RFC3339, text); err == nil {
+		return instant, instant, false, nil
+	}
+
+	offset, err := parseQueryDuration(text)
+	if err != nil {
+		return start, end, false, fmt.Errorf("invalid date %q. Use YYYY-MM-DD, an RFC 3339 timestamp, today, tomorrow, yesterday, or a duration like 7d", text)
+	}
+	return now.Add(offset), now.Add(offset), true, nil
+}
+
+// parseQueryDuration parses a duration in hours (h), days (d) or weeks (w)
+func parseQueryDuration(text string) (time.Duration, error) {
+	if len(text) < 2 {
+		return 0, errors.New("invalid duration")
+	}
+
+	unit := time.Hour
+	switch text[len(text)-1] {
+	case 'h':
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, errors.New("invalid duration")
+	}
+
+	n, err := strconv.Atoi(text[:len(text)-1])
+	if err != nil || n > 10000 || n < -10000 {
+		return 0, errors.New("invalid duration")
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func valueError(value queryValue, format string, args ...interface{}) error {
+	return &QueryError{Position: value.pos + 1, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+func TestParseTodoQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []queryTerm
+	}{
+		{name: "empty", input: "  ", want: nil},
+		{
+			name:  "words",
+			input: "buy  milk",
+			want: []queryTerm{
+				{pos: 0, values: []queryValue{{pos: 0, text: "buy"}}},
+				{pos: 5, values: []queryValue{{pos: 5, text: "milk"}}},
+			},
+		},
+		{
+			name:  "phrase with escapes",
+			input: `"say \"hi\" \\ bye"`,
+			want:  []queryTerm{{pos: 0, values: []queryValue{{pos: 0, text: `say "hi" \ bye`}}}},
+		},
+		{
+			name:  "field with values",
+			input: "Priority:high,medium",
+			want: []queryTerm{
+				{pos: 0, field: "priority", values: []queryValue{{pos: 9, text: "high"}, {pos: 14, text: "medium"}}},
+			},
+		},
+		{
+			name:  "negated field with quoted value",
+			input: `-category:"Home office",work`,
+			want: []queryTerm{
+				{pos: 0, negated: true, field: "category", values: []queryValue{{pos: 10, text: "Home office"}, {pos: 24, text: "work"}}},
+			},
+		},
+		{
+			name:  "negated word",
+			input: "-draft is:overdue",
+			want: []queryTerm{
+				{pos: 0, negated: true, values: []queryValue{{pos: 1, text: "draft"}}},
+				{pos: 7, field: "is", values: []queryValue{{pos: 10, text: "overdue"}}},
+			},
+		},
+		{
+			// Positions count characters, not bytes
+			name:  "multibyte characters",
+			input: "café due:today",
+			want: []queryTerm{
+				{pos: 0, values: []queryValue{{pos: 0, text: "café"}}},
+				{pos: 5, field: "due", values: []queryValue{{pos: 9, text: "today"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTodoQuery(tt.input)
+			if err != nil {
+				t.Fatalf("parseTodoQuery(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTodoQuery(%q) =\n%+v\nwant\n%+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTodoQueryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position int
+		message  string
+	}{
+		{name: "dash without term", input: "milk - bread", position: 6, message: "expected a term after '-'"},
+		{name: "dash at the end", input: "milk -", position: 6, message: "expected a term after '-'"},
+		{name: "unknown field", input: "is:open color:red", position: 9, message: `unknown field "color". Allowed fields: priority, category, tag, due, created, is, has`},
+		{name: "missing value", input: "priority: high", position: 10, message: `expected a value for "priority"`},
+		{name: "missing value at the end", input: "tag:", position: 5, message: `expected a value for "tag"`},
+		{name: "empty value in list", input: "priority:high,,low", position: 15, message: `expected a value for "priority"`},
+		{name: "trailing comma", input: "priority:high,", position: 15, message: `expected a value for "priority"`},
+		{name: "quote inside value", input: `tag:a"b`, position: 6, message: `unexpected '"' in value`},
+		{name: "unterminated quote", input: `buy "milk`, position: 5, message: "unterminated quoted string"},
+		{name: "unterminated quoted value", input: `category:"Home`, position: 10, message: "unterminated quoted string"},
+		{name: "empty quoted string", input: `milk ""`, position: 6, message: "empty quoted string"},
+		{name: "text after phrase", input: `"milk"x`, position: 7, message: "expected a space after quoted string"},
+		{name: "text after quoted value", input: `category:"Home"x`, position: 16, message: "expected a space or ',' after value"},
+		{name: "multibyte position", input: "café -", position: 6, message: "expected a term after '-'"},
+		{name: "too long", input: strings.Repeat("a", maxQueryLength+1), position: maxQueryLength + 1, message: "query is longer than 500 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTodoQuery(tt.input)
+			assertQueryError(t, err, tt.position, tt.message)
+		})
+	}
+}
+
+func TestApplyTodoQuery(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 4, 5, 0, time.UTC)
+	today := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    string
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:     "priority values are combined with OR",
+			input:    "priority:HIGH,low",
+			wantSQL:  `(todos.priority IN ($1,$2))`,
+			wantVars: []interface{}{models.PriorityHigh, models.PriorityLow},
+		},
+		{
+			name:     "negated term",
+			input:    "-is:completed",
+			wantSQL:  `NOT ((todos.completed = $1))`,
+			wantVars: []interface{}{true},
+		},
+		{
+			name:     "terms are combined with AND",
+			input:    "has:description is:recurring",
+			wantSQL:  `((COALESCE(todos.description, '') <> '')) AND ((COALESCE(todos.recurrence_rule, '') <> ''))`,
+			wantVars: []interface{}{},
+		},
+		{
+			name:     "before a day",
+			input:    "due:<2026-11-01",
+			wantSQL:  `((todos.due_date IS NOT NULL AND todos.due_date < $1))`,
+			wantVars: []interface{}{day},
+		},
+		{
+			name:     "until the end of a day",
+			input:    "due:<=2026-11-01",
+			wantSQL:  `((todos.due_date IS NOT NULL AND todos.due_date < $1))`,
+			wantVars: []interface{}{day.AddDate(0, 0, 1)},
+		},
+		{
+			name:     "whole day",
+			input:    "created:today",
+			wantSQL:  `((todos.created_at IS NOT NULL AND todos.created_at >= $1 AND todos.created_at < $2))`,
+			wantVars: []interface{}{today, today.AddDate(0, 0, 1)},
+		},
+		{
+			name:     "duration from now",
+			input:    "due:7d",
+			wantSQL:  `((todos.due_date IS NOT NULL AND todos.due_date >= $1 AND todos.due_date < $2))`,
+			wantVars: []interface{}{now, now.AddDate(0, 0, 7)},
+		},
+		{
+			name:     "duration in the past",
+			input:    "created:-2w",
+			wantSQL:  `((todos.created_at IS NOT NULL AND todos.created_at >= $1 AND todos.created_at < $2))`,
+			wantVars: []interface{}{now.AddDate(0, 0, -14), now},
+		},
+		{
+			name:     "text uses full text search",
+			input:    `"buy milk"`,
+			wantSQL:  `(todos.search_vector @@ phraseto_tsquery('` + searchConfig + `', $1))`,
+			wantVars: []interface{}{"buy milk"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := applyTodoQuery(dryRunDB(t).Table("todos"), tt.input, now)
+			if err != nil {
+				t.Fatalf("applyTodoQuery(%q) error = %v", tt.input, err)
+			}
+
+			var rows []map[string]interface{}
+			db := query.Find(&rows)
+
+			want := `SELECT * FROM "todos" WHERE ` + tt.wantSQL
+			if got := dryRunSQL(t, db); got != want {
+				t.Errorf("applyTodoQuery(%q) SQL =\n%s\nwant\n%s", tt.input, got, want)
+			}
+			if !reflect.DeepEqual(db.Statement.Vars, tt.wantVars) {
+				t.Errorf("applyTodoQuery(%q) vars = %#v, want %#v", tt.input, db.Statement.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestApplyTodoQueryValueErrors(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    string
+		position int
+		message  string
+	}{
+		{name: "invalid priority", input: "priority:high,urgent", position: 15, message: `invalid priority "urgent". Must be 'high', 'medium', or 'low'`},
+		{name: "invalid tag", input: `is:open tag:" "`, position: 13, message: `invalid tag " "`},
+		{name: "invalid is", input: "is:closed", position: 4, message: `invalid value "closed" for is. Must be 'completed', 'done', 'open', 'pending', 'overdue', or 'recurring'`},
+		{name: "invalid has", input: "has:owner", position: 5, message: `invalid value "owner" for has. Must be 'description', 'due', 'tags', 'items', or 'reminders'`},
+		{name: "operator without date", input: "due:<=", position: 5, message: `expected a date after "<="`},
+		{
+			// The position points past the operator
+			name:     "invalid date",
+			input:    "due:>=2026-13-01",
+			position: 7,
+			message:  `invalid date "2026-13-01". Use YYYY-MM-DD, an RFC 3339 timestamp, today, tomorrow, yesterday, or a duration like 7d`,
+		},
+		{name: "invalid duration", input: "created:7y", position: 9, message: `invalid date "7y". Use YYYY-MM-DD, an RFC 3339 timestamp, today, tomorrow, yesterday, or a duration like 7d`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := applyTodoQuery(dryRunDB(t).Table("todos"), tt.input, now)
+			assertQueryError(t, err, tt.position, tt.message)
+		})
+	}
+}
+
+func TestTodoQueryOnEngine(t *testing.T) {
+	userID := newListTodos(t)
+	s := NewTodoService(testDB)
+	inOneDay := time.Now().Add(24 * time.Hour).UTC().Format("2006-01-02")
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"priority:high", []string{"Alpha", "Delta"}},
+		{"priority:HIGH,low", []string{"Alpha", "Bravo", "Delta", "Echo"}},
+		{"-priority:high", []string{"Bravo", "Charlie", "Echo"}},
+		{"category:home", []string{"Alpha", "Charlie", "Echo"}},
+		{`-category:"Work"`, []string{"Alpha", "Charlie", "Echo"}},
+		{"tag:Urgent", []string{"Alpha"}},
+		{"-tag:urgent", []string{"Bravo", "Charlie", "Delta", "Echo"}},
+		{"is:done", []string{"Bravo"}},
+		{"is:open", []string{"Alpha", "Charlie", "Delta", "Echo"}},
+		{"is:overdue", []string{"Echo"}},
+		{"is:recurring", []string{"Charlie"}},
+		{"is:overdue,recurring", []string{"Charlie", "Echo"}},
+		{"has:description", []string{"Alpha"}},
+		{"has:due", []string{"Alpha", "Charlie", "Echo"}},
+		{"-has:due", []string{"Bravo", "Delta"}},
+		{"has:tags", []string{"Alpha"}},
+		{"has:items", []string{"Delta"}},
+		{"has:reminders", []string{"Charlie"}},
+		{"due:<today", []string{"Echo"}},
+		{"due:>=tomorrow", []string{"Alpha", "Charlie"}},
+		{"due:<=" + inOneDay, []string{"Alpha", "Echo"}},
+		{"due:>" + inOneDay, []string{"Charlie"}},
+		{"due:" + inOneDay, []string{"Alpha"}},
+		{"due:2d", []string{"Alpha"}},
+		{"due:-3d", []string{"Echo"}},
+		{"created:-1h", []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"}},
+		{"created:>tomorrow", nil},
+		{`"quarterly report"`, []string{"Alpha"}},
+		{"board", []string{"Alpha"}},
+		{"-report", []string{"Bravo", "Charlie", "Delta", "Echo"}},
+		{"is:open priority:high -has:due", []string{"Delta"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			todos, _, err := s.GetTodos(userID, models.PaginationParams{Query: tt.query, Sort: "title"})
+			if err != nil {
+				t.Fatalf("GetTodos() error = %v", err)
+			}
+			if got := todoTitles(todos); len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("GetTodos(q=%s) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// assertQueryError checks that err is a QueryError at the 1-based position
+func assertQueryError(t *testing.T, err error, position int, message string) {
+	t.Helper()
+
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("error = %v, want a *QueryError", err)
+	}
+	if queryErr.Position != position || queryErr.Message != message {
+		t.Errorf("error at position %d: %q, want position %d: %q", queryErr.Position, queryErr.Message, position, message)
+	}
+	if !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("error = %v, want it to wrap ErrInvalidQuery", err)
+	}
+}
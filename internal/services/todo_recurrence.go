@@ -0,0 +1,154 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+var errRecurrenceDueDateRequired = InvalidField("due_date_required", "due_date", "due_date is required for recurring todos")
+
+// setRecurrence validates the rule and starts a new series at the todo's due
+// date. A rule with an empty frequency removes the recurrence. The series ID
+// is the todo's own ID, so for new todos it is filled in after creation.
+func setRecurrence(todo *models.Todo, rule *models.RecurrenceRule) error {
+	rule.Normalize()
+	if rule.Frequency == "" {
+		todo.RecurrenceRule = ""
+		todo.RecurrenceStart = nil
+		todo.RecurrenceSeriesID = nil
+		todo.RecurrenceIndex = 0
+		return nil
+	}
+
+	if err := rule.Validate(); err != nil {
+		return InvalidField("invalid_recurrence", "recurrence", err.Error())
+	}
+	if todo.DueDate == nil {
+		return errRecurrenceDueDateRequired
+	}
+
+	start := *todo.DueDate
+	todo.RecurrenceRule = rule.String()
+	todo.RecurrenceStart = &start
+	todo.RecurrenceIndex = 1
+	if todo.ID != 0 {
+		todo.RecurrenceSeriesID = &todo.ID
+	}
+	return nil
+}
+
+// todoOccurrences returns the occurrences of the todo's series that come after
+// the todo itself, up to count
+func todoOccurrences(todo *models.Todo, count int) ([]time.Time, error) {
+	if todo.RecurrenceRule == "" || todo.RecurrenceStart == nil {
+		return nil, ErrTodoNotRecurring
+	}
+
+	rule, err := models.ParseRecurrenceRule(todo.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := rule.Occurrences(*todo.RecurrenceStart, todo.RecurrenceIndex+count)
+	if len(occurrences) <= todo.RecurrenceIndex {
+		return []time.Time{}, nil
+	}
+
+	return occurrences[todo.RecurrenceIndex:], nil
+}
+
+// spawnNextOccurrence creates the next todo of a recurring series when an
+// instance is completed. It copies the todo with its tags, reminders and
+// unchecked checklist items, and does nothing if the series has ended or the next
+// occurrence already exists (e.g. the todo was reopened and completed again).
+func spawnNextOccurrence(db *gorm.DB, todo *models.Todo) error {
+	if todo.RecurrenceRule == "" || todo.RecurrenceSeriesID == nil {
+		return nil
+	}
+
+	occurrences, err := todoOccurrences(todo, 1)
+	if err != nil {
+		return err
+	}
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	var count int64
+	err = db.Model(&models.Todo{}).
+		Where("recurrence_series_id = ? AND recurrence_index = ?", *todo.RecurrenceSeriesID, todo.RecurrenceIndex+1).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to check next occurrence: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var tags []models.Tag
+	if err := db.Model(todo).Association("Tags").Find(&tags); err != nil {
+		return fmt.Errorf("failed to get todo tags: %w", err)
+	}
+
+	var items []models.TodoItem
+	if err := db.Where("todo_id = ?", todo.ID).Order("position ASC, id ASC").Find(&items).Error; err != nil {
+		return fmt.Errorf("failed to get todo items: %w", err)
+	}
+
+	offsets, err := reminderOffsets(db, todo.ID)
+	if err != nil {
+		return err
+	}
+
+	dueDate := occurrences[0]
+	next := models.Todo{
+		WorkspaceID:        todo.WorkspaceID,
+		UserID:             todo.UserID,
+		Title:              todo.Title,
+		Description:        todo.Description,
+		AutoComplete:       todo.AutoComplete,
+		CategoryID:         todo.CategoryID,
+		Priority:           todo.Priority,
+		DueDate:            &dueDate,
+		RecurrenceRule:     todo.RecurrenceRule,
+		RecurrenceStart:    todo.RecurrenceStart,
+		RecurrenceSeriesID: todo.RecurrenceSeriesID,
+		RecurrenceIndex:    todo.RecurrenceIndex + 1,
+		Tags:               tags,
+	}
+
+	if err := db.Create(&next).Error; err != nil {
+		return fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+
+	if err := replaceReminders(db, &next, offsets); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		copied := models.TodoItem{
+			TodoID:   next.ID,
+			Title:    item.Title,
+			Position: item.Position,
+		}
+		if err := db.Create(&copied).Error; err != nil {
+			return fmt.Errorf("failed to copy todo item: %w", err)
+		}
+	}
+
+	return emitTodoEvent(db, models.EventTodoCreated, &next)
+}
+
+// Get the next occurrences of a recurring todo
+func (s *TodoService) GetOccurrences(userID, id uint, count int) ([]time.Time, error) {
+	todo, err := getTodoForUser(s.db, userID, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return todoOccurrences(todo, count)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+// searchConfig is the text search configuration of the todos.search_vector
+// column (migrations/up/014_add_todo_search.up.sql). "simple" does not stem
+// words, so todos written in any language are matched the same way.
+const searchConfig = "simple"
+
+// searchQuery parses the search with websearch_to_tsquery, which supports
+// "quoted phrases", -exclusion and OR
+const searchQuery = "websearch_to_tsquery('" + searchConfig + "', ?)"
+
+// Highlights are marked with private use characters so the text can be
+// HTML-escaped before the markers are turned into <mark> tags
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+
+	titleHeadlineOptions   = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", HighlightAll=true"
+	snippetHeadlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + `, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "`
+)
+
+var highlightReplacer = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// isPostgres reports whether full-text search is available
+func isPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
+
+// applyTodoSearch matches the search against the title and description
+func applyTodoSearch(query *gorm.DB, search string) *gorm.DB {
+	if isPostgres(query) {
+		return query.Where("todos.search_vector @@ "+searchQuery, search)
+	}
+
+	// Databases without full-text search fall back to substring matching
+	terms := parseLikeSearch(search)
+	if len(terms.groups) == 0 && len(terms.excluded) == 0 {
+		return query.Where("1 = 0")
+	}
+	for _, group := range terms.groups {
+		var parts []string
+		var args []interface{}
+		for _, text := range group {
+			cond := likeCondition(text)
+			parts = append(parts, "("+cond.sql+")")
+			args = append(args, cond.args...)
+		}
+		query = query.Where(strings.Join(parts, " OR "), args...)
+	}
+	for _, text := range terms.excluded {
+		cond := likeCondition(text)
+		query = query.Where("NOT ("+cond.sql+")", cond.args...)
+	}
+	return query
+}
+
+// selectTodoSearch adds the relevance (search_rank) and the highlighted title
+// and description snippet to the selected columns. It is applied after
+// counting, since the extra columns cannot be counted.
+func selectTodoSearch(query *gorm.DB, search string) *gorm.DB {
+	if !isPostgres(query) {
+		return selectLikeRank(query, search)
+	}
+
+	return query.Select(
+		"todos.*, "+
+			"ts_rank(todos.search_vector, "+searchQuery+") AS search_rank, "+
+			"ts_headline('"+searchConfig+"', todos.title, "+searchQuery+", ?) AS search_title, "+
+			"ts_headline('"+searchConfig+"', COALESCE(todos.description, ''), "+searchQuery+", ?) AS search_snippet",
+		search,
+		search, titleHeadlineOptions,
+		search, snippetHeadlineOptions,
+	)
+}
+
+// highlightSearch escapes the headlines and marks the matched words with
+// <mark> tags. Postgres returns the headlines with the todos; on other
+// databases they are built here.
+func highlightSearch(db *gorm.DB, todos []models.Todo, search string) {
+	if search != "" && !isPostgres(db) {
+		headlineLikeSearch(todos, search)
+	}
+
+	for i := range todos {
+		todos[i].SearchTitle = highlightReplacer.Replace(html.EscapeString(todos[i].SearchTitle))
+		todos[i].SearchSnippet = highlightReplacer.Replace(html.EscapeString(todos[i].SearchSnippet))
+	}
+}
+
+// likeSearch is a search parsed like websearch_to_tsquery, for databases
+// without full-text search. Every group must match, a group matches when any
+// of its terms does, and no excluded term may match.
+type likeSearch struct {
+	groups   [][]string
+	excluded []string
+}
+
+// Weights of a matched term in the title and the description, like the A and
+// B weights of todos.search_vector
+const (
+	likeTitleWeight       = "1.0"
+	likeDescriptionWeight = "0.4"
+)
+
+// likeSnippetWords is the length of the description snippet, like the
+// MaxWords headline option
+const likeSnippetWords = 35
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// parseLikeSearch splits the search into words and "quoted phrases". A word
+// or phrase starting with - is excluded, and OR between two terms puts them
+// in the same group.
+func parseLikeSearch(search string) likeSearch {
+	var result likeSearch
+	or := false
+	for len(search) > 0 {
+		search = strings.TrimLeft(search, " \t\r\n")
+		if search == "" {
+			break
+		}
+
+		exclude := false
+		if search[0] == '-' {
+			exclude = true
+			search = search[1:]
+		}
+
+		var text string
+		if strings.HasPrefix(search, `"`) {
+			end := strings.Index(search[1:], `"`)
+			if end < 0 {
+				text, search = search[1:], ""
+			} else {
+				text, search = search[1:end+1], search[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(search, " \t\r\n")
+			if end < 0 {
+				end = len(search)
+			}
+			text, search = search[:end], search[end:]
+			if !exclude && strings.EqualFold(text, "or") {
+				or = len(result.groups) > 0
+				continue
+			}
+		}
+
+		text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+		if text == "" {
+			continue
+		}
+		switch {
+		case exclude:
+			result.excluded = append(result.excluded, text)
+		case or:
+			last := len(result.groups) - 1
+			result.groups[last] = append(result.groups[last], text)
+		default:
+			result.groups = append(result.groups, []string{text})
+		}
+		or = false
+	}
+	return result
+}
+
+// terms returns the terms of every group
+func (s likeSearch) terms() []string {
+	var terms []string
+	for _, group := range s.groups {
+		terms = append(terms, group...)
+	}
+	return terms
+}
+
+// likeCondition matches a lowercase word or phrase in the title or
+// description
+func likeCondition(text string) queryCondition {
+	pattern := "%" + likeEscaper.Replace(text) + "%"
+	return queryCondition{
+		sql:  `LOWER(todos.title) LIKE ? ESCAPE '\' OR LOWER(COALESCE(todos.description, '')) LIKE ? ESCAPE '\'`,
+		args: []interface{}{pattern, pattern},
+	}
+}
+
+// selectLikeRank ranks the todos by the terms they contain, weighing the
+// title more than the description
+func selectLikeRank(query *gorm.DB, search string) *gorm.DB {
+	terms := parseLikeSearch(search).terms()
+	if len(terms) == 0 {
+		return query.Select("todos.*, 0 AS search_rank")
+	}
+
+	var parts []string
+	var args []interface{}
+	for _, text := range terms {
+		pattern := "%" + likeEscaper.Replace(text) + "%"
+		parts = append(parts,
+			`CASE WHEN LOWER(todos.title) LIKE ? ESCAPE '\' THEN `+likeTitleWeight+` ELSE 0 END`,
+			`CASE WHEN LOWER(COALESCE(todos.description, '')) LIKE ? ESCAPE '\' THEN `+likeDescriptionWeight+` ELSE 0 END`,
+		)
+		args = append(args, pattern, pattern)
+	}
+	return query.Select("todos.*, ("+strings.Join(parts, " + ")+") AS search_rank", args...)
+}
+
+// headlineLikeSearch builds the title and description snippet headlines,
+// marking the terms of the search
+func headlineLikeSearch(todos []models.Todo, search string) {
+	terms := parseLikeSearch(search).terms()
+	if len(terms) == 0 {
+		return
+	}
+
+	patterns := make([]string, len(terms))
+	for i, text := range terms {
+		patterns[i] = regexp.QuoteMeta(text)
+	}
+	matcher := regexp.MustCompile("(?i)" + strings.Join(patterns, "|"))
+	mark := func(text string) string {
+		return matcher.ReplaceAllString(text, highlightStart+"${0}"+highlightStop)
+	}
+
+	for i := range todos {
+		todos[i].SearchTitle = mark(todos[i].Title)
+
+		// The snippet starts a few words before the first match
+		words := strings.Fields(todos[i].Description)
+		start := 0
+		for j, word := range words {
+			if matcher.MatchString(word) {
+				start = max(j-5, 0)
+				break
+			}
+		}
+		end := min(start+likeSnippetWords, len(words))
+		todos[i].SearchSnippet = mark(strings.Join(words[start:end], " "))
+	}
+}
@@ -3,65 +3,115 @@ package services
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
-	"github.com/jayasaleh/todo-list/be/internal/database"
 	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+	"github.com/jayasaleh/todo-list/be/internal/repository"
 )
 
+// TodoService is the GORM implementation of repository.TodoRepository
 type TodoService struct {
 	db *gorm.DB
 }
 
-func NewTodoService() *TodoService {
+var _ repository.TodoRepository = (*TodoService)(nil)
+
+func NewTodoService(db *gorm.DB) *TodoService {
 	return &TodoService{
-		db: database.GetDB(),
+		db: db,
 	}
 }
 
 // Create Todo
-func (s *TodoService) CreateTodo(req models.CreateTodoRequest) (*models.Todo, error) {
+func (s *TodoService) CreateTodo(userID uint, req models.CreateTodoRequest) (*models.Todo, error) {
 	todo := models.Todo{
-		Title:       req.Title,
-		Description: req.Description,
-		Priority:    req.Priority,
-		DueDate:     req.DueDate,
+		UserID:       userID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Priority:     req.Priority,
+		DueDate:      req.DueDate,
+		AutoComplete: req.AutoComplete,
 	}
 
 	// Validate category exists (required)
 	var category models.Category
-	if err := s.db.First(&category, req.CategoryID).Error; err != nil {
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&category, req.CategoryID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, ErrTodoCategoryNotFound
 		}
 		return nil, fmt.Errorf("failed to validate category: %w", err)
 	}
 	todo.CategoryID = req.CategoryID
 
+	// Todos live in the workspace of their category
+	if err := requireWorkspaceWrite(s.db, category.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+	todo.WorkspaceID = category.WorkspaceID
+
+	// Tags must belong to the same workspace as the todo
+	tags, err := findWorkspaceTags(s.db, todo.WorkspaceID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	todo.Tags = tags
+
 	// Validate priority (required)
 	if !models.ValidatePriority(req.Priority) {
-		return nil, errors.New("invalid priority value. Must be 'high', 'medium', or 'low'")
+		return nil, ErrInvalidPriority
 	}
 	todo.Priority = req.Priority
 
-	if err := s.db.Create(&todo).Error; err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+	if req.Recurrence != nil {
+		if err := setRecurrence(&todo, req.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+
+	offsets, err := normalizeReminderOffsets(req.ReminderOffsets)
+	if err != nil {
+		return nil, err
+	}
+
+	err = realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Create(&todo).Error; err != nil {
+			return fmt.Errorf("failed to create todo: %w", err)
+		}
+
+		// A new recurring todo starts its own series
+		if todo.RecurrenceRule != "" {
+			todo.RecurrenceSeriesID = &todo.ID
+			if err := tx.Model(&todo).Update("recurrence_series_id", todo.ID).Error; err != nil {
+				return fmt.Errorf("failed to create todo: %w", err)
+			}
+		}
+
+		if err := replaceReminders(tx, &todo, offsets); err != nil {
+			return err
+		}
+
+		return emitTodoEvent(tx, models.EventTodoCreated, &todo)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Preload category (required field)
-	s.db.Preload("Category").First(&todo, todo.ID)
+	preloadTodo(s.db).First(&todo, todo.ID)
 
 	return &todo, nil
 }
 
 // Get Todo by ID
-func (s *TodoService) GetTodoByID(id uint) (*models.Todo, error) {
+func (s *TodoService) GetTodoByID(userID, id uint) (*models.Todo, error) {
 	var todo models.Todo
 
-	if err := s.db.Preload("Category").First(&todo, id).Error; err != nil {
+	if err := preloadTodo(s.db).Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&todo, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, fmt.Errorf("failed to get todo: %w", err)
 	}
@@ -70,24 +120,25 @@ func (s *TodoService) GetTodoByID(id uint) (*models.Todo, error) {
 }
 
 // Get Todos with Pagination
-func (s *TodoService) GetTodos(params models.PaginationParams) ([]models.Todo, *models.Pagination, error) {
+func (s *TodoService) GetTodos(userID uint, params models.PaginationParams) ([]models.Todo, *models.Pagination, error) {
 	var todos []models.Todo
 	var total int64
 
-	query := s.db.Model(&models.Todo{}).Preload("Category")
-
-	if params.Search != "" {
-		searchPattern := "%" + params.Search + "%"
-		query = query.Where("LOWER(title) LIKE LOWER(?)", searchPattern)
+	sorts, err := ParseTodoSort(params)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, nil, fmt.Errorf("failed to count todos: %w", err)
+	query := preloadTodo(s.db.Model(&models.Todo{})).
+		Where("todos.workspace_id IN (?)", memberWorkspaceIDs(s.db, userID))
+	if params.WorkspaceID != 0 {
+		query = query.Where("todos.workspace_id = ?", params.WorkspaceID)
 	}
-
-	page := params.Page
-	if page < 1 {
-		page = 1
+	query = applyTodoFilters(query, params)
+	if params.Query != "" {
+		if query, err = applyTodoQuery(query, params.Query, time.Now()); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	limit := params.Limit
@@ -98,26 +149,30 @@ func (s *TodoService) GetTodos(params models.PaginationParams) ([]models.Todo, *
 		limit = 50
 	}
 
-	offset := (page - 1) * limit
-
-	sortBy := params.SortBy
-	if sortBy == "" {
-		sortBy = "created_at"
+	if isCursorMode(params) {
+		return getTodosByCursor(query, sorts, limit, params)
 	}
 
-	sortOrder := params.SortOrder
-	if sortOrder == "" {
-		sortOrder = "desc"
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count todos: %w", err)
 	}
-	if sortOrder != "asc" && sortOrder != "desc" {
-		sortOrder = "desc"
+
+	page := params.Page
+	if page < 1 {
+		page = 1
 	}
 
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+	offset := (page - 1) * limit
+
+	if params.Search != "" {
+		query = selectTodoSearch(query, params.Search)
+	}
+	query = applyTodoSort(query, sorts)
 
 	if err := query.Offset(offset).Limit(limit).Find(&todos).Error; err != nil {
 		return nil, nil, fmt.Errorf("failed to get todos: %w", err)
 	}
+	highlightSearch(query, todos, params.Search)
 
 	totalPages := int(total) / limit
 	if int(total)%limit != 0 {
@@ -134,17 +189,133 @@ func (s *TodoService) GetTodos(params models.PaginationParams) ([]models.Todo, *
 	return todos, pagination, nil
 }
 
+// preloadTodo loads the relations returned with every todo
+func preloadTodo(db *gorm.DB) *gorm.DB {
+	return db.Preload("Category").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name ASC")
+		}).
+		Preload("Reminders", func(db *gorm.DB) *gorm.DB {
+			return db.Order("remind_at ASC")
+		}).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, id ASC")
+		})
+}
+
+// ValidateTodoFilters checks that the filter values are valid and consistent.
+// Errors name the invalid query parameter.
+func ValidateTodoFilters(params models.PaginationParams) error {
+	for _, priority := range params.Priorities {
+		if !models.ValidatePriority(priority) {
+			return InvalidField("invalid_filter", "priority", "invalid priority filter. Must be 'high', 'medium', or 'low'")
+		}
+	}
+
+	for _, categoryID := range params.CategoryIDs {
+		if categoryID == 0 {
+			return InvalidField("invalid_filter", "category_id", "invalid category_id filter")
+		}
+	}
+
+	if params.TagMode != "" && params.TagMode != "any" && params.TagMode != "all" {
+		return InvalidField("invalid_filter", "tag_mode", "invalid tag_mode. Must be 'any' or 'all'")
+	}
+
+	if params.DueBefore != nil && params.DueAfter != nil && !params.DueAfter.Before(*params.DueBefore) {
+		return InvalidField("invalid_filter", "due_after", "due_after must be earlier than due_before")
+	}
+
+	if params.Pagination != "" && params.Pagination != "offset" && params.Pagination != "cursor" {
+		return InvalidField("invalid_filter", "pagination", "invalid pagination mode. Must be 'offset' or 'cursor'")
+	}
+
+	if params.CreatedBefore != nil && params.CreatedAfter != nil && !params.CreatedAfter.Before(*params.CreatedBefore) {
+		return InvalidField("invalid_filter", "created_after", "created_after must be earlier than created_before")
+	}
+
+	return nil
+}
+
+// applyTodoFilters adds the search and filter conditions to the query.
+// It is applied before counting so the pagination total matches the result set.
+func applyTodoFilters(query *gorm.DB, params models.PaginationParams) *gorm.DB {
+	if params.Search != "" {
+		query = applyTodoSearch(query, params.Search)
+	}
+
+	if len(params.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", params.CategoryIDs)
+	}
+	if len(params.Priorities) > 0 {
+		query = query.Where("priority IN ?", params.Priorities)
+	}
+	if params.Completed != nil {
+		query = query.Where("completed = ?", *params.Completed)
+	}
+	if names := tagFilterNames(params.Tags); len(names) > 0 {
+		// "any" matches todos with at least one of the tags, "all" requires every tag
+		if params.TagMode == "all" {
+			query = query.Where("todos.id IN (SELECT todo_tags.todo_id FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.name IN ? GROUP BY todo_tags.todo_id HAVING COUNT(DISTINCT tags.name) = ?)", names, len(names))
+		} else {
+			query = query.Where("todos.id IN (SELECT todo_tags.todo_id FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.name IN ?)", names)
+		}
+	}
+
+	// Lower bounds are inclusive, upper bounds are exclusive
+	if params.DueAfter != nil {
+		query = query.Where("due_date >= ?", *params.DueAfter)
+	}
+	if params.DueBefore != nil {
+		query = query.Where("due_date < ?", *params.DueBefore)
+	}
+	if params.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *params.CreatedAfter)
+	}
+	if params.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *params.CreatedBefore)
+	}
+
+	// Overdue means not completed and due date already passed
+	if params.Overdue {
+		query = query.Where("completed = ? AND due_date IS NOT NULL AND due_date < ?", false, time.Now())
+	}
+
+	return query
+}
+
+// tagFilterNames normalizes and deduplicates the tag names of a filter
+func tagFilterNames(tags []string) []string {
+	var names []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name := models.NormalizeTagName(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
 // Update Todo
-func (s *TodoService) UpdateTodo(id uint, req models.UpdateTodoRequest) (*models.Todo, error) {
+func (s *TodoService) UpdateTodo(userID, id uint, req models.UpdateTodoRequest) (*models.Todo, error) {
 	var todo models.Todo
 
-	if err := s.db.First(&todo, id).Error; err != nil {
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&todo, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, fmt.Errorf("failed to get todo: %w", err)
 	}
 
+	if err := requireWorkspaceWrite(s.db, todo.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	wasCompleted := todo.Completed
+
 	if req.Title != nil {
 		todo.Title = *req.Title
 	}
@@ -157,9 +328,9 @@ func (s *TodoService) UpdateTodo(id uint, req models.UpdateTodoRequest) (*models
 	if req.CategoryID != nil {
 		// Validate category exists (required if provided)
 		var category models.Category
-		if err := s.db.First(&category, *req.CategoryID).Error; err != nil {
+		if err := s.db.Where("workspace_id = ?", todo.WorkspaceID).First(&category, *req.CategoryID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, errors.New("category not found")
+				return nil, ErrTodoCategoryNotFound
 			}
 			return nil, fmt.Errorf("failed to validate category: %w", err)
 		}
@@ -167,64 +338,169 @@ func (s *TodoService) UpdateTodo(id uint, req models.UpdateTodoRequest) (*models
 	}
 	if req.Priority != nil {
 		if !models.ValidatePriority(*req.Priority) {
-			return nil, errors.New("invalid priority value. Must be 'high', 'medium', or 'low'")
+			return nil, ErrInvalidPriority
 		}
 		todo.Priority = *req.Priority
 	}
 	if req.DueDate != nil {
 		todo.DueDate = req.DueDate
+		// Moving the first occurrence moves the whole series
+		if todo.RecurrenceIndex == 1 {
+			start := *todo.DueDate
+			todo.RecurrenceStart = &start
+		}
+	}
+	if req.Recurrence != nil {
+		if err := setRecurrence(&todo, req.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+	if req.AutoComplete != nil {
+		todo.AutoComplete = *req.AutoComplete
+		if todo.AutoComplete {
+			if err := syncAutoComplete(s.db, &todo); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var tags []models.Tag
+	if req.TagIDs != nil {
+		found, err := findWorkspaceTags(s.db, todo.WorkspaceID, *req.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+		tags = found
+	}
+
+	var offsets []int
+	if req.ReminderOffsets != nil {
+		normalized, err := normalizeReminderOffsets(*req.ReminderOffsets)
+		if err != nil {
+			return nil, err
+		}
+		offsets = normalized
 	}
 
-	if err := s.db.Save(&todo).Error; err != nil {
-		return nil, fmt.Errorf("failed to update todo: %w", err)
+	err := realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Save(&todo).Error; err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+
+		if req.TagIDs != nil {
+			if err := tx.Model(&todo).Association("Tags").Replace(tags); err != nil {
+				return fmt.Errorf("failed to update todo tags: %w", err)
+			}
+		}
+
+		if req.ReminderOffsets != nil {
+			if err := replaceReminders(tx, &todo, offsets); err != nil {
+				return err
+			}
+		} else if req.DueDate != nil {
+			if err := rescheduleReminders(tx, &todo); err != nil {
+				return err
+			}
+		}
+
+		if !wasCompleted && todo.Completed {
+			if err := spawnNextOccurrence(tx, &todo); err != nil {
+				return err
+			}
+			return emitTodoEvent(tx, models.EventTodoCompleted, &todo)
+		}
+
+		return emitTodoEvent(tx, models.EventTodoUpdated, &todo)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Preload category (required field)
-	s.db.Preload("Category").First(&todo, todo.ID)
+	preloadTodo(s.db).First(&todo, todo.ID)
 
 	return &todo, nil
 }
 
 // DeleteTodo - Delete todo
-func (s *TodoService) DeleteTodo(id uint) error {
+func (s *TodoService) DeleteTodo(userID, id uint) error {
 	var todo models.Todo
 
 	// Find by ID
-	if err := s.db.First(&todo, id).Error; err != nil {
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&todo, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("todo not found")
+			return ErrTodoNotFound
 		}
 		return fmt.Errorf("failed to get todo: %w", err)
 	}
 
-	// Delete from DB
-	if err := s.db.Delete(&todo).Error; err != nil {
+	if err := requireWorkspaceWrite(s.db, todo.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	return realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		return deleteTodo(tx, &todo)
+	})
+}
+
+// deleteTodo soft deletes a todo and emits the event. It must be called in a
+// transaction.
+func deleteTodo(tx *gorm.DB, todo *models.Todo) error {
+	// Load the todo as it was for the webhook payload
+	if err := preloadTodo(tx).First(todo, todo.ID).Error; err != nil {
+		return fmt.Errorf("failed to get todo: %w", err)
+	}
+
+	if err := tx.Delete(todo).Error; err != nil {
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
 
-	return nil
+	return emitTodoEvent(tx, models.EventTodoDeleted, todo)
 }
 
 // Toggle Todo Complete
-func (s *TodoService) ToggleComplete(id uint) (*models.Todo, error) {
+func (s *TodoService) ToggleComplete(userID, id uint) (*models.Todo, error) {
 	var todo models.Todo
 
-	if err := s.db.First(&todo, id).Error; err != nil {
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&todo, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, fmt.Errorf("failed to get todo: %w", err)
 	}
 
+	if err := requireWorkspaceWrite(s.db, todo.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
 	todo.Completed = !todo.Completed
 
-	if err := s.db.Save(&todo).Error; err != nil {
-		return nil, fmt.Errorf("failed to toggle todo completion: %w", err)
+	err := realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		return saveCompletion(tx, &todo)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if todo.CategoryID != nil {
-		s.db.Preload("Category").First(&todo, todo.ID)
-	}
+	// Preload category (required field)
+	preloadTodo(s.db).First(&todo, todo.ID)
 
 	return &todo, nil
 }
+
+// saveCompletion saves a changed completion status. Completing a recurring
+// todo creates its next occurrence. It must be called in a transaction.
+func saveCompletion(tx *gorm.DB, todo *models.Todo) error {
+	if err := tx.Save(todo).Error; err != nil {
+		return fmt.Errorf("failed to toggle todo completion: %w", err)
+	}
+
+	if todo.Completed {
+		if err := spawnNextOccurrence(tx, todo); err != nil {
+			return err
+		}
+		return emitTodoEvent(tx, models.EventTodoCompleted, todo)
+	}
+
+	return emitTodoEvent(tx, models.EventTodoUpdated, todo)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+// ErrInvalidSort is returned when the requested sort spec contains an unknown field
+var ErrInvalidSort = InvalidField("invalid_sort", "sort", "invalid sort field")
+
+const defaultTodoSort = "-created_at"
+
+// sortColumn describes how a sortable field maps to SQL
+type sortColumn struct {
+	expr      string
+	nullsLast bool
+}
+
+// todoSortColumns is the whitelist of fields allowed in the sort spec.
+// Only expressions from this map are ever interpolated into ORDER BY.
+var todoSortColumns = map[string]sortColumn{
+	"id":         {expr: "todos.id"},
+	"title":      {expr: "todos.title"},
+	"priority":   {expr: "CASE todos.priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END"},
+	"completed":  {expr: "todos.completed"},
+	"due_date":   {expr: "todos.due_date", nullsLast: true},
+	"created_at": {expr: "todos.created_at"},
+	"updated_at": {expr: "todos.updated_at"},
+	// relevance is the search_rank column added by selectTodoSearch
+	"relevance": {expr: "search_rank"},
+}
+
+// TodoSort is a single field of a parsed sort spec
+type TodoSort struct {
+	Field string
+	Desc  bool
+}
+
+// AllowedTodoSortFields returns the sortable field names in a stable order
+func AllowedTodoSortFields() []string {
+	return []string{"id", "title", "priority", "completed", "due_date", "created_at", "updated_at", "relevance"}
+}
+
+// ParseTodoSort parses a sort spec like "-priority,due_date,id".
+// A leading "-" sorts descending. The legacy sort_by/sort_order params are
+// used when sort is empty. Searches are sorted by relevance by default, except
+// with cursor pagination. The id column is always appended as a tie-breaker.
+func ParseTodoSort(params models.PaginationParams) ([]TodoSort, error) {
+	spec := params.Sort
+	if spec == "" && params.SortBy != "" {
+		spec = params.SortBy
+		if params.SortOrder != "asc" {
+			spec = "-" + spec
+		}
+	}
+	if spec == "" {
+		spec = defaultTodoSort
+		if params.Search != "" && !isCursorMode(params) {
+			spec = "-relevance"
+		}
+	}
+
+	var sorts []TodoSort
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		sort := TodoSort{Field: part}
+		if strings.HasPrefix(part, "-") {
+			sort = TodoSort{Field: part[1:], Desc: true}
+		} else if strings.HasPrefix(part, "+") {
+			sort = TodoSort{Field: part[1:]}
+		}
+
+		if _, ok := todoSortColumns[sort.Field]; !ok {
+			return nil, fmt.Errorf("%w %q. Allowed fields: %s", ErrInvalidSort, sort.Field, strings.Join(AllowedTodoSortFields(), ", "))
+		}
+		if sort.Field == "relevance" {
+			// The rank only exists for searches and floats cannot be compared reliably in a cursor
+			if params.Search == "" {
+				return nil, fmt.Errorf("%w: relevance requires a search query", ErrInvalidSort)
+			}
+			if isCursorMode(params) {
+				return nil, fmt.Errorf("%w: relevance cannot be used with cursor pagination", ErrInvalidSort)
+			}
+		}
+		if seen[sort.Field] {
+			continue
+		}
+		seen[sort.Field] = true
+		sorts = append(sorts, sort)
+	}
+
+	if !seen["id"] {
+		sorts = append(sorts, TodoSort{Field: "id"})
+	}
+
+	return sorts, nil
+}
+
+// applyTodoSort adds the ORDER BY clauses for the parsed sort spec
+func applyTodoSort(query *gorm.DB, sorts []TodoSort) *gorm.DB {
+	for _, sort := range sorts {
+		column := todoSortColumns[sort.Field]
+
+		order := column.expr + " ASC"
+		if sort.Desc {
+			order = column.expr + " DESC"
+		}
+		if column.nullsLast {
+			order += " NULLS LAST"
+		}
+
+		query = query.Order(order)
+	}
+
+	return query
+}
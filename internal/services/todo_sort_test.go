@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+func TestParseTodoSort(t *testing.T) {
+	tests := []struct {
+		name   string
+		params models.PaginationParams
+		want   []TodoSort
+	}{
+		{
+			name:   "default",
+			params: models.PaginationParams{},
+			want:   []TodoSort{{Field: "created_at", Desc: true}, {Field: "id"}},
+		},
+		{
+			name:   "search defaults to relevance",
+			params: models.PaginationParams{Search: "milk"},
+			want:   []TodoSort{{Field: "relevance", Desc: true}, {Field: "id"}},
+		},
+		{
+			name:   "search with cursor keeps the default",
+			params: models.PaginationParams{Search: "milk", Pagination: "cursor"},
+			want:   []TodoSort{{Field: "created_at", Desc: true}, {Field: "id"}},
+		},
+		{
+			name:   "multiple fields",
+			params: models.PaginationParams{Sort: "-priority, +due_date,title"},
+			want:   []TodoSort{{Field: "priority", Desc: true}, {Field: "due_date"}, {Field: "title"}, {Field: "id"}},
+		},
+		{
+			name:   "explicit id is not appended again",
+			params: models.PaginationParams{Sort: "-id,title"},
+			want:   []TodoSort{{Field: "id", Desc: true}, {Field: "title"}},
+		},
+		{
+			name:   "duplicates keep the first direction",
+			params: models.PaginationParams{Sort: "title,-title"},
+			want:   []TodoSort{{Field: "title"}, {Field: "id"}},
+		},
+		{
+			name:   "empty parts are skipped",
+			params: models.PaginationParams{Sort: ",title,,"},
+			want:   []TodoSort{{Field: "title"}, {Field: "id"}},
+		},
+		{
+			name:   "legacy sort_by descending by default",
+			params: models.PaginationParams{SortBy: "title"},
+			want:   []TodoSort{{Field: "title", Desc: true}, {Field: "id"}},
+		},
+		{
+			name:   "legacy sort_by ascending",
+			params: models.PaginationParams{SortBy: "title", SortOrder: "asc"},
+			want:   []TodoSort{{Field: "title"}, {Field: "id"}},
+		},
+		{
+			name:   "sort wins over sort_by",
+			params: models.PaginationParams{Sort: "due_date", SortBy: "title"},
+			want:   []TodoSort{{Field: "due_date"}, {Field: "id"}},
+		},
+		{
+			name:   "relevance with search",
+			params: models.PaginationParams{Sort: "relevance", Search: "milk"},
+			want:   []TodoSort{{Field: "relevance"}, {Field: "id"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTodoSort(tt.params)
+			if err != nil {
+				t.Fatalf("ParseTodoSort() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTodoSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTodoSortRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  models.PaginationParams
+		message string
+	}{
+		{
+			name:    "unknown field",
+			params:  models.PaginationParams{Sort: "title,password"},
+			message: `invalid sort field "password". Allowed fields: id, title, priority, completed, due_date, created_at, updated_at, relevance`,
+		},
+		{
+			name:    "sql injection",
+			params:  models.PaginationParams{Sort: "title; DROP TABLE todos"},
+			message: `invalid sort field "title; DROP TABLE todos"`,
+		},
+		{
+			name:    "column of another table",
+			params:  models.PaginationParams{Sort: "-categories.name"},
+			message: `invalid sort field "categories.name"`,
+		},
+		{
+			name:    "legacy sort_by",
+			params:  models.PaginationParams{SortBy: "user_id"},
+			message: `invalid sort field "user_id"`,
+		},
+		{
+			name:    "relevance without search",
+			params:  models.PaginationParams{Sort: "-relevance"},
+			message: "invalid sort field: relevance requires a search query",
+		},
+		{
+			name:    "relevance with cursor",
+			params:  models.PaginationParams{Sort: "-relevance", Search: "milk", Cursor: "abc"},
+			message: "invalid sort field: relevance cannot be used with cursor pagination",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTodoSort(tt.params)
+			if !errors.Is(err, ErrInvalidSort) {
+				t.Fatalf("ParseTodoSort() error = %v, want ErrInvalidSort", err)
+			}
+			if !strings.HasPrefix(err.Error(), tt.message) {
+				t.Errorf("ParseTodoSort() error = %q, want prefix %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestApplyTodoSort(t *testing.T) {
+	sorts := []TodoSort{{Field: "priority", Desc: true}, {Field: "due_date"}, {Field: "id"}}
+
+	var todos []models.Todo
+	got := dryRunSQL(t, applyTodoSort(dryRunDB(t).Model(&models.Todo{}), sorts).Find(&todos))
+
+	want := "ORDER BY CASE todos.priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END DESC,todos.due_date ASC NULLS LAST,todos.id ASC"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("applyTodoSort() SQL = %q, want suffix %q", got, want)
+	}
+}
+
+func TestTodoSortOnEngine(t *testing.T) {
+	userID := newListTodos(t)
+	s := NewTodoService(testDB)
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"id", []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"}},
+		{"-id", []string{"Echo", "Delta", "Charlie", "Bravo", "Alpha"}},
+		{"title", []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"}},
+		{"-title", []string{"Echo", "Delta", "Charlie", "Bravo", "Alpha"}},
+		// Priorities sort by rank, not alphabetically
+		{"priority", []string{"Bravo", "Echo", "Charlie", "Alpha", "Delta"}},
+		{"-priority", []string{"Alpha", "Delta", "Charlie", "Bravo", "Echo"}},
+		{"completed", []string{"Alpha", "Charlie", "Delta", "Echo", "Bravo"}},
+		{"-completed", []string{"Bravo", "Alpha", "Charlie", "Delta", "Echo"}},
+		// Todos without a due date come last in both directions
+		{"due_date", []string{"Echo", "Alpha", "Charlie", "Bravo", "Delta"}},
+		{"-due_date", []string{"Charlie", "Alpha", "Echo", "Bravo", "Delta"}},
+		{"created_at", []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"}},
+		{"-created_at", []string{"Echo", "Delta", "Charlie", "Bravo", "Alpha"}},
+		{"-updated_at", []string{"Bravo", "Echo", "Delta", "Charlie", "Alpha"}},
+		{"-priority,due_date", []string{"Alpha", "Delta", "Charlie", "Echo", "Bravo"}},
+		{"completed,-due_date,-id", []string{"Charlie", "Alpha", "Echo", "Delta", "Bravo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			todos, _, err := s.GetTodos(userID, models.PaginationParams{Sort: tt.sort})
+			if err != nil {
+				t.Fatalf("GetTodos() error = %v", err)
+			}
+			if got := todoTitles(todos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTodos(sort=%s) = %q, want %q", tt.sort, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("relevance", func(t *testing.T) {
+		todos, _, err := s.GetTodos(userID, models.PaginationParams{Search: "report", Sort: "-relevance"})
+		if err != nil {
+			t.Fatalf("GetTodos() error = %v", err)
+		}
+		if got := todoTitles(todos); !reflect.DeepEqual(got, []string{"Alpha"}) {
+			t.Errorf("GetTodos() = %q, want [Alpha]", got)
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/realtime"
+)
+
+var errCategoryInTrash = Conflict("category_in_trash", "the category of the todo is in the trash. Restore the category first or pass restore_category=true")
+
+// TrashService lists, restores and permanently deletes soft-deleted todos and
+// categories
+type TrashService struct {
+	db        *gorm.DB
+	retention time.Duration
+}
+
+func NewTrashService(db *gorm.DB, retention time.Duration) *TrashService {
+	return &TrashService{
+		db:        db,
+		retention: retention,
+	}
+}
+
+// Get Trash returns the deleted todos and categories of the user's workspaces,
+// most recently deleted first
+func (s *TrashService) GetTrash(userID uint, params models.TrashParams) ([]models.TrashItem, *models.Pagination, error) {
+	workspaceIDs := memberWorkspaceIDs(s.db, userID)
+	if params.WorkspaceID != 0 {
+		workspaceIDs = workspaceIDs.Where("workspace_members.workspace_id = ?", params.WorkspaceID)
+	}
+
+	var parts []string
+	var args []interface{}
+	if params.Type == "" || params.Type == models.TrashTypeTodo {
+		parts = append(parts, "SELECT 'todo' AS type, id, workspace_id, title AS name, category_id, deleted_at FROM todos WHERE deleted_at IS NOT NULL AND workspace_id IN (?)")
+		args = append(args, workspaceIDs)
+	}
+	if params.Type == "" || params.Type == models.TrashTypeCategory {
+		parts = append(parts, "SELECT 'category' AS type, id, workspace_id, name, NULL AS category_id, deleted_at FROM categories WHERE deleted_at IS NOT NULL AND workspace_id IN (?)")
+		args = append(args, workspaceIDs)
+	}
+	if len(parts) == 0 {
+		return nil, nil, InvalidField("invalid_trash_type", "type", "invalid trash type. Must be 'todo' or 'category'")
+	}
+	trash := s.db.Raw(strings.Join(parts, " UNION ALL "), args...)
+
+	var total int64
+	if err := s.db.Table("(?) AS trash", trash).Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count trash: %w", err)
+	}
+
+	limit := params.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * limit
+
+	var items []models.TrashItem
+	if err := s.db.Table("(?) AS trash", trash).
+		Order("deleted_at DESC, type DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Scan(&items).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+	if s.retention > 0 {
+		for i := range items {
+			purgeAt := items[i].DeletedAt.Add(s.retention)
+			items[i].PurgeAt = &purgeAt
+		}
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	pagination := &models.Pagination{
+		CurrentPage: page,
+		PerPage:     limit,
+		Total:       total,
+		TotalPages:  totalPages,
+	}
+
+	return items, pagination, nil
+}
+
+// Restore Todo moves a todo out of the trash. If its category is in the trash
+// as well, it is only restored with the todo when withCategory is set.
+func (s *TrashService) RestoreTodo(userID, id uint, withCategory bool) (*models.Todo, error) {
+	todo, err := s.trashedTodo(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Categories referenced by a todo cannot be purged, so it is always found
+	var category models.Category
+	if err := s.db.Unscoped().First(&category, todo.CategoryID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category.DeletedAt.Valid && !withCategory {
+		return nil, errCategoryInTrash
+	}
+
+	err = realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		if category.DeletedAt.Valid {
+			if err := restoreCategory(tx, &category); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Model(todo).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+
+		return emitTodoEvent(tx, models.EventTodoRestored, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var restored models.Todo
+	if err := preloadTodo(s.db).First(&restored, todo.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get todo: %w", err)
+	}
+
+	return &restored, nil
+}
+
+// Restore Category moves a category out of the trash. Its deleted todos stay
+// in the trash.
+func (s *TrashService) RestoreCategory(userID, id uint) (*models.Category, error) {
+	category, err := s.trashedCategory(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		return restoreCategory(tx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Purge Todo deletes a todo in the trash permanently, together with its
+// checklist items, tags and reminders
+func (s *TrashService) PurgeTodo(userID, id uint) error {
+	todo, err := s.trashedTodo(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		_, err := purgeTodos(tx, "id = ?", todo.ID)
+		return err
+	})
+}
+
+// Purge Category deletes a category in the trash permanently. Its todos in the
+// trash cannot be restored without it and are deleted as well.
+func (s *TrashService) PurgeCategory(userID, id uint) error {
+	category, err := s.trashedCategory(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return realtime.Transaction(s.db, func(tx *gorm.DB) error {
+		if _, err := purgeTodos(tx, "category_id = ? AND deleted_at IS NOT NULL", category.ID); err != nil {
+			return err
+		}
+
+		return purgeCategory(tx, category)
+	})
+}
+
+// trashedTodo returns a deleted todo the user may change
+func (s *TrashService) trashedTodo(userID, id uint) (*models.Todo, error) {
+	var todo models.Todo
+
+	if err := s.db.Unscoped().Where("workspace_id IN (?) AND deleted_at IS NOT NULL", memberWorkspaceIDs(s.db, userID)).First(&todo, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NotFound("todo_not_found", "todo not found in trash")
+		}
+		return nil, fmt.Errorf("failed to get todo: %w", err)
+	}
+
+	if err := requireWorkspaceWrite(s.db, todo.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	return &todo, nil
+}
+
+// trashedCategory returns a deleted category the user may change
+func (s *TrashService) trashedCategory(userID, id uint) (*models.Category, error) {
+	var category models.Category
+
+	if err := s.db.Unscoped().Where("workspace_id IN (?) AND deleted_at IS NOT NULL", memberWorkspaceIDs(s.db, userID)).First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NotFound("category_not_found", "category not found in trash")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	if err := requireWorkspaceWrite(s.db, category.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// purgeTodos permanently deletes the todos matching the condition together
+// with their checklist items, reminders and tags, and emits todo.purged for
+// each. The migrations cascade these deletes, but databases baselined from
+// the schema of older versions (created by GORM) have no cascading foreign
+// keys, so they are deleted explicitly.
+func purgeTodos(tx *gorm.DB, condition string, args ...interface{}) (int64, error) {
+	var todos []models.Todo
+	if err := tx.Unscoped().Where(condition, args...).Find(&todos).Error; err != nil {
+		return 0, fmt.Errorf("failed to get todos: %w", err)
+	}
+	if len(todos) == 0 {
+		return 0, nil
+	}
+
+	ids := tx.Unscoped().Model(&models.Todo{}).Select("id").Where(condition, args...)
+
+	for _, table := range []string{"todo_items", "reminders", "todo_tags"} {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE todo_id IN (?)", ids).Error; err != nil {
+			return 0, fmt.Errorf("failed to delete %s: %w", strings.ReplaceAll(table, "_", " "), err)
+		}
+	}
+
+	result := tx.Unscoped().Where(condition, args...).Delete(&models.Todo{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete todos: %w", result.Error)
+	}
+
+	for i := range todos {
+		if err := emitTodoEvent(tx, models.EventTodoPurged, &todos[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	return result.RowsAffected, nil
+}
+
+// purgeCategory permanently deletes a category and emits category.purged
+func purgeCategory(tx *gorm.DB, category *models.Category) error {
+	if err := tx.Unscoped().Delete(category).Error; err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	return emitCategoryEvent(tx, models.EventCategoryPurged, category)
+}
+
+func restoreCategory(tx *gorm.DB, category *models.Category) error {
+	if err := tx.Unscoped().Model(category).Update("deleted_at", nil).Error; err != nil {
+		return fmt.Errorf("failed to restore category: %w", err)
+	}
+
+	return emitCategoryEvent(tx, models.EventCategoryRestored, category)
+}
+
+// TrashPurger permanently deletes todos and categories that have been in the
+// trash for longer than the retention period. Purging is idempotent, so it
+// can run on several replicas.
+type TrashPurger struct {
+	db        *gorm.DB
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashPurger(db *gorm.DB, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		db:        db,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run purges the trash periodically until the context is cancelled
+func (p *TrashPurger) Run(ctx context.Context) {
+	if p.retention <= 0 || p.interval <= 0 {
+		log.Println("Trash purger disabled")
+		return
+	}
+
+	log.Printf("Trash purger started (retention %s, interval %s)", p.retention, p.interval)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.RunOnce(ctx); err != nil {
+			log.Printf("Trash purger: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce deletes the expired trash and returns the number of deleted todos
+// and categories
+func (p *TrashPurger) RunOnce(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-p.retention)
+	var purged int64
+
+	err := realtime.Transaction(p.db.WithContext(ctx), func(tx *gorm.DB) error {
+		todos, err := purgeTodos(tx, "deleted_at < ?", cutoff)
+		if err != nil {
+			return err
+		}
+
+		// Categories are kept while todos (restorable ones included) still use them
+		var categories []models.Category
+		if err := tx.Unscoped().
+			Where("deleted_at < ?", cutoff).
+			Where("id NOT IN (SELECT category_id FROM todos)").
+			Find(&categories).Error; err != nil {
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+		for i := range categories {
+			if err := purgeCategory(tx, &categories[i]); err != nil {
+				return err
+			}
+		}
+
+		purged = todos + int64(len(categories))
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/notifier"
+)
+
+const (
+	webhookBatchSize   = 50
+	webhookLease       = 2 * time.Minute
+	webhookTimeout     = 10 * time.Second
+	webhookMaxAttempts = 8
+
+	// Retries wait 30s, 1m, 2m, 4m, ... up to an hour between attempts
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = time.Hour
+
+	// webhookMaxErrorLength limits how much of a response body is logged
+	webhookMaxErrorLength = 500
+)
+
+// WebhookDispatcher sends pending webhook deliveries from the outbox. Like the
+// reminder scheduler it can run on several replicas: deliveries are claimed
+// with SELECT ... FOR UPDATE SKIP LOCKED and leased by moving next_attempt_at.
+// A batch can take longer than the lease, so each delivery renews its lease
+// right before it is sent and is skipped if another replica took it over.
+type WebhookDispatcher struct {
+	db       *gorm.DB
+	client   *http.Client
+	interval time.Duration
+}
+
+func NewWebhookDispatcher(db *gorm.DB, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db:       db,
+		client:   newWebhookClient(webhookTimeout),
+		interval: interval,
+	}
+}
+
+// Run polls for pending deliveries until the context is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	if d.interval <= 0 {
+		log.Println("Webhook dispatcher disabled")
+		return
+	}
+
+	log.Printf("Webhook dispatcher started (interval %s)", d.interval)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.RunOnce(ctx); err != nil {
+			log.Printf("Webhook dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce claims and sends one batch of pending deliveries and returns the
+// number of deliveries that succeeded
+func (d *WebhookDispatcher) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := d.claim(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		leased, err := d.renew(delivery)
+		if err != nil {
+			log.Printf("Failed to renew webhook lease %d: %v", delivery.ID, err)
+			continue
+		}
+		if !leased {
+			continue
+		}
+
+		if err := d.deliver(ctx, delivery); err != nil {
+			log.Printf("Failed to deliver webhook %d: %v", delivery.ID, err)
+			continue
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// claim locks a batch of pending deliveries that are due for this replica.
+// NextAttemptAt of the returned deliveries is their lease.
+func (d *WebhookDispatcher) claim(now time.Time) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	lease := webhookLeaseUntil(now)
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Subscription").
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("id ASC").
+			Limit(webhookBatchSize).
+			Find(&deliveries).Error
+		if err != nil {
+			return fmt.Errorf("failed to get pending webhooks: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+			deliveries[i].NextAttemptAt = lease
+		}
+
+		if err := tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", lease).Error; err != nil {
+			return fmt.Errorf("failed to claim webhooks: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// renew extends the lease of a claimed delivery before it is sent. It reports
+// false when the lease expired and another replica claimed the delivery, or
+// when it is no longer pending.
+func (d *WebhookDispatcher) renew(delivery models.WebhookDelivery) (bool, error) {
+	result := d.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, models.DeliveryStatusPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", webhookLeaseUntil(time.Now()))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// webhookLeaseUntil returns the end of a lease starting now. It is rounded to
+// seconds, so that it compares equal after a round trip through the database.
+func webhookLeaseUntil(now time.Time) time.Time {
+	return now.Add(webhookLease).Truncate(time.Second)
+}
+
+// deliver posts a claimed delivery and records the outcome
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	subscription := delivery.Subscription
+	if subscription == nil || !subscription.Active {
+		return d.record(delivery, models.DeliveryStatusFailed, 0, "webhook is disabled")
+	}
+
+	statusCode, err := d.post(ctx, subscription, delivery)
+	if err == nil {
+		return d.record(delivery, models.DeliveryStatusDelivered, statusCode, "")
+	}
+
+	status := models.DeliveryStatusPending
+	if delivery.Attempts+1 >= webhookMaxAttempts {
+		status = models.DeliveryStatusFailed
+	}
+	if recordErr := d.record(delivery, status, statusCode, err.Error()); recordErr != nil {
+		log.Printf("Failed to record webhook failure: %v", recordErr)
+	}
+	return err
+}
+
+// post sends the payload signed with the subscription secret
+func (d *WebhookDispatcher) post(ctx context.Context, subscription *models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-list-webhooks")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Event-ID", delivery.EventID)
+	req.Header.Set("X-Webhook-Delivery-ID", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Signature-256", "sha256="+notifier.Sign(subscription.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxErrorLength))
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	return resp.StatusCode, nil
+}
+
+// record stores the outcome of an attempt. Pending deliveries are scheduled
+// again with exponential backoff.
+func (d *WebhookDispatcher) record(delivery models.WebhookDelivery, status string, statusCode int, lastError string) error {
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{
+		"status":           status,
+		"attempts":         attempts,
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+	}
+
+	switch status {
+	case models.DeliveryStatusDelivered:
+		updates["delivered_at"] = time.Now()
+	case models.DeliveryStatusPending:
+		updates["next_attempt_at"] = time.Now().Add(webhookBackoff(attempts))
+	}
+
+	if err := d.db.Model(&delivery).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// webhookBackoff returns the delay before the next attempt after the given
+// number of failed attempts
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+	"github.com/jayasaleh/todo-list/be/internal/notifier"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, webhookMaxBackoff},
+		{20, webhookMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// newTestWebhook subscribes a URL to every event of the workspace. It is
+// stored directly because CreateWebhook rejects local URLs.
+func newTestWebhook(t *testing.T, userID, workspaceID uint, url string) *models.WebhookSubscription {
+	t.Helper()
+
+	webhook := models.WebhookSubscription{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		URL:         url,
+		Events:      models.EventAll,
+		Secret:      WebhookSecretPrefix + "test",
+		Active:      true,
+	}
+	if err := testDB.Create(&webhook).Error; err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+	// Other tests send the pending deliveries of every webhook
+	t.Cleanup(func() {
+		testDB.Where("subscription_id = ?", webhook.ID).Delete(&models.WebhookDelivery{})
+	})
+	return &webhook
+}
+
+// webhookDeliveries returns the deliveries of a webhook in order
+func webhookDeliveries(t *testing.T, subscriptionID uint) []models.WebhookDelivery {
+	t.Helper()
+
+	var deliveries []models.WebhookDelivery
+	if err := testDB.Where("subscription_id = ?", subscriptionID).Order("id ASC").Find(&deliveries).Error; err != nil {
+		t.Fatalf("failed to get deliveries: %v", err)
+	}
+	return deliveries
+}
+
+// newTestDispatcher returns a dispatcher that may send to local test servers
+func newTestDispatcher(server *httptest.Server) *WebhookDispatcher {
+	d := NewWebhookDispatcher(testDB, time.Minute)
+	if server != nil {
+		d.client = server.Client()
+	}
+	return d
+}
+
+func TestWebhookOutboxOnEngine(t *testing.T) {
+	userID := newTestUser(t)
+	category := newTestCategory(t, userID, "Outbox")
+	webhook := newTestWebhook(t, userID, category.WorkspaceID, "https://example.com/hook")
+
+	due := time.Now().Add(48 * time.Hour)
+	report := newTestTodo(t, userID, models.CreateTodoRequest{Title: "Report", CategoryID: category.ID, Priority: models.PriorityLow, DueDate: &due})
+	standup := newTestTodo(t, userID, models.CreateTodoRequest{
+		Title:      "Standup",
+		CategoryID: category.ID,
+		Priority:   models.PriorityLow,
+		DueDate:    &due,
+		Recurrence: &models.RecurrenceRule{Frequency: models.FrequencyDaily, Interval: 1},
+	})
+
+	deliveries := webhookDeliveries(t, webhook.ID)
+	if len(deliveries) != 2 || deliveries[0].Event != models.EventTodoCreated || deliveries[0].Status != models.DeliveryStatusPending {
+		t.Fatalf("deliveries = %+v, want two pending todo.created", deliveries)
+	}
+	var payload webhookEvent
+	if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil {
+		t.Fatalf("invalid payload %s: %v", deliveries[0].Payload, err)
+	}
+	if payload.ID != deliveries[0].EventID || payload.WorkspaceID != category.WorkspaceID || !strings.Contains(deliveries[0].Payload, `"title":"Report"`) {
+		t.Errorf("payload = %s", deliveries[0].Payload)
+	}
+
+	// The update of the first todo is rolled back together with its delivery
+	_, rolledBack, err := NewTodoService(testDB).BulkUpdate(userID, models.BulkTodoRequest{
+		IDs:          []uint{report.ID, standup.ID},
+		Action:       models.BulkActionSetDueDate,
+		AllOrNothing: true,
+	})
+	if err != nil || !rolledBack {
+		t.Fatalf("BulkUpdate() = %v, %v, want a rollback", rolledBack, err)
+	}
+	if got := webhookDeliveries(t, webhook.ID); len(got) != 2 {
+		t.Errorf("deliveries after rollback = %+v, want only the two todo.created", got)
+	}
+}
+
+func TestWebhookDispatcherOnEngine(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	var status atomic.Int64
+	status.Store(http.StatusOK)
+	requests := make(chan request, webhookMaxAttempts+1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{header: r.Header, body: body}
+		w.WriteHeader(int(status.Load()))
+		io.WriteString(w, "service unavailable")
+	}))
+	defer server.Close()
+
+	userID := newTestUser(t)
+	category := newTestCategory(t, userID, "Dispatch")
+	webhook := newTestWebhook(t, userID, category.WorkspaceID, server.URL)
+	d := newTestDispatcher(server)
+
+	t.Run("delivered", func(t *testing.T) {
+		newTestTodo(t, userID, models.CreateTodoRequest{Title: "Send invoice", CategoryID: category.ID, Priority: models.PriorityLow})
+		if _, err := d.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+
+		delivery := webhookDeliveries(t, webhook.ID)[0]
+		if delivery.Status != models.DeliveryStatusDelivered || delivery.Attempts != 1 || delivery.DeliveredAt == nil || delivery.LastStatusCode != http.StatusOK {
+			t.Errorf("delivery = %+v, want delivered", delivery)
+		}
+
+		req := <-requests
+		if string(req.body) != delivery.Payload {
+			t.Errorf("body = %s, want the payload %s", req.body, delivery.Payload)
+		}
+		if got, want := req.header.Get("X-Signature-256"), "sha256="+notifier.Sign(webhook.Secret, req.body); got != want {
+			t.Errorf("X-Signature-256 = %q, want %q", got, want)
+		}
+		if req.header.Get("X-Webhook-Event") != models.EventTodoCreated || req.header.Get("X-Webhook-Event-ID") != delivery.EventID ||
+			req.header.Get("X-Webhook-Delivery-ID") != strconv.FormatUint(uint64(delivery.ID), 10) {
+			t.Errorf("headers = %v", req.header)
+		}
+	})
+
+	t.Run("retries until failed", func(t *testing.T) {
+		status.Store(http.StatusServiceUnavailable)
+		newTestTodo(t, userID, models.CreateTodoRequest{Title: "Book flight", CategoryID: category.ID, Priority: models.PriorityLow})
+		id := webhookDeliveries(t, webhook.ID)[1].ID
+
+		for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+			before := time.Now()
+			if _, err := d.RunOnce(context.Background()); err != nil {
+				t.Fatalf("RunOnce() error = %v", err)
+			}
+			<-requests
+
+			var delivery models.WebhookDelivery
+			if err := testDB.First(&delivery, id).Error; err != nil {
+				t.Fatalf("failed to get delivery: %v", err)
+			}
+			if delivery.Attempts != attempt || delivery.LastStatusCode != http.StatusServiceUnavailable || !strings.Contains(delivery.LastError, "service unavailable") {
+				t.Fatalf("attempt %d: delivery = %+v", attempt, delivery)
+			}
+			if attempt == webhookMaxAttempts {
+				if delivery.Status != models.DeliveryStatusFailed {
+					t.Errorf("delivery after %d attempts = %s, want failed", attempt, delivery.Status)
+				}
+				break
+			}
+
+			wantNext := before.Add(webhookBackoff(attempt))
+			if delivery.Status != models.DeliveryStatusPending || delivery.NextAttemptAt.Before(wantNext.Add(-time.Second)) || delivery.NextAttemptAt.After(wantNext.Add(5*time.Second)) {
+				t.Fatalf("attempt %d: delivery %s at %v, want pending at about %v", attempt, delivery.Status, delivery.NextAttemptAt, wantNext)
+			}
+
+			// Nothing is sent before the backoff has passed
+			if _, err := d.RunOnce(context.Background()); err != nil {
+				t.Fatalf("RunOnce() error = %v", err)
+			}
+			if len(requests) != 0 {
+				t.Fatalf("attempt %d: retried before the backoff", attempt)
+			}
+
+			// Let the backoff pass
+			if err := testDB.Model(&delivery).Update("next_attempt_at", before.Add(-time.Second)).Error; err != nil {
+				t.Fatalf("failed to expire the backoff: %v", err)
+			}
+		}
+
+		if _, err := d.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+		if len(requests) != 0 {
+			t.Errorf("failed delivery was sent again")
+		}
+	})
+}
+
+func TestWebhookRenewOnEngine(t *testing.T) {
+	userID := newTestUser(t)
+	category := newTestCategory(t, userID, "Renew")
+	webhook := newTestWebhook(t, userID, category.WorkspaceID, "https://example.com/hook")
+	newTestTodo(t, userID, models.CreateTodoRequest{Title: "Renew passport", CategoryID: category.ID, Priority: models.PriorityLow})
+	id := webhookDeliveries(t, webhook.ID)[0].ID
+
+	d := newTestDispatcher(nil)
+	find := func(deliveries []models.WebhookDelivery) models.WebhookDelivery {
+		t.Helper()
+		for _, delivery := range deliveries {
+			if delivery.ID == id {
+				return delivery
+			}
+		}
+		t.Fatalf("delivery %d was not claimed", id)
+		return models.WebhookDelivery{}
+	}
+
+	now := time.Now()
+	mine := find(mustClaim(t, d, now))
+
+	// The lease is still held, so no other replica can claim the delivery
+	for _, delivery := range mustClaim(t, d, now) {
+		if delivery.ID == id {
+			t.Fatalf("claim() during the lease returned delivery %d", id)
+		}
+	}
+
+	// The lease expired while this replica was busy and another one took over
+	theirs := find(mustClaim(t, d, now.Add(webhookLease+time.Second)))
+
+	if leased, err := d.renew(mine); err != nil || leased {
+		t.Errorf("renew() of the expired lease = %v, %v, want false", leased, err)
+	}
+	if leased, err := d.renew(theirs); err != nil || !leased {
+		t.Errorf("renew() of the current lease = %v, %v, want true", leased, err)
+	}
+}
+
+func mustClaim(t *testing.T, d *WebhookDispatcher, now time.Time) []models.WebhookDelivery {
+	t.Helper()
+
+	deliveries, err := d.claim(now)
+	if err != nil {
+		t.Fatalf("claim() error = %v", err)
+	}
+	return deliveries
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errWebhookPrivateAddress is returned for webhook URLs that point into a
+// private network. Webhook URLs are chosen by users, so the server must not
+// be usable to reach internal services or cloud metadata endpoints.
+var errWebhookPrivateAddress = InvalidField("private_url", "url", "invalid webhook url. The host must resolve to a public IP address")
+
+// nonPublicPrefixes are special-purpose ranges that netip does not classify
+// as private, loopback or link-local
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, embeds IPv4 addresses
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4, embeds IPv4 addresses
+	netip.MustParsePrefix("2001::/32"),      // Teredo, embeds IPv4 addresses
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+}
+
+// isPublicIP reports whether webhooks may be sent to the address. Loopback,
+// private and link-local addresses (169.254.169.254 serves cloud metadata)
+// are rejected, also when mapped into IPv6.
+func isPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkWebhookHost resolves the host of a webhook URL and rejects it unless
+// every address is public
+func checkWebhookHost(ctx context.Context, host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !isPublicIP(ip) {
+			return errWebhookPrivateAddress
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(ips) == 0 {
+		return InvalidField("invalid_url", "url", fmt.Sprintf("invalid webhook url. Host %q could not be resolved", host))
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return errWebhookPrivateAddress
+		}
+	}
+	return nil
+}
+
+// newWebhookClient returns the HTTP client used to send webhooks. The host
+// may resolve to another address than when the webhook was saved, so the
+// address is checked again on every connection. Redirects are not followed
+// and proxies are not used, since either would bypass that check.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("invalid webhook address %q: %w", address, err)
+			}
+			if !isPublicIP(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not a public IP address", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
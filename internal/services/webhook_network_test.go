@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"127.1.2.3", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"198.18.0.1", false},
+		{"255.255.255.255", false},
+		{"224.0.0.1", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"fd12:3456::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"2002:a9fe:a9fe::1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := isPublicIP(netip.MustParseAddr(tt.ip)); got != tt.want {
+				t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{url: "https://93.184.216.34/hooks", wantErr: nil},
+		{url: " http://[2606:4700:4700::1111]:8443/hooks ", wantErr: nil},
+		{url: "ftp://93.184.216.34/hooks", wantErr: ErrValidation},
+		{url: "/hooks", wantErr: ErrValidation},
+		{url: "http://127.0.0.1:8080/hooks", wantErr: errWebhookPrivateAddress},
+		{url: "http://localhost/hooks", wantErr: errWebhookPrivateAddress},
+		{url: "http://169.254.169.254/latest/meta-data/", wantErr: errWebhookPrivateAddress},
+		{url: "http://[::ffff:10.0.0.1]/hooks", wantErr: errWebhookPrivateAddress},
+		{url: "http://[fe80::1%25eth0]/hooks", wantErr: errWebhookPrivateAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, err := validateWebhookURL(tt.url)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("validateWebhookURL() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateWebhookURL() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The address is checked when dialing, after any DNS resolution
+	resp, err := newWebhookClient(time.Second).Post(server.URL, "application/json", nil)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("webhook client connected to a loopback address")
+	}
+}
+
+func TestWebhookClientDoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	// Use the redirect policy with a transport that may reach the test server
+	client := newWebhookClient(time.Second)
+	client.Transport = http.DefaultTransport
+
+	resp, err := client.Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+// WebhookSecretPrefix marks generated webhook signing secrets
+const WebhookSecretPrefix = "whsec_"
+
+type WebhookService struct {
+	db *gorm.DB
+}
+
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{
+		db: db,
+	}
+}
+
+// validateWebhookURL checks that the URL is an absolute http(s) URL whose
+// host resolves to public addresses only
+func validateWebhookURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", InvalidField("invalid_url", "url", "invalid webhook url. Must be an absolute http or https URL")
+	}
+	if err := checkWebhookHost(context.Background(), u.Hostname()); err != nil {
+		return "", err
+	}
+	return rawURL, nil
+}
+
+// normalizeWebhookEvents validates and deduplicates an event filter. An empty
+// filter subscribes to every event.
+func normalizeWebhookEvents(events []string) (string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !models.ValidateWebhookEvent(event) {
+			return "", InvalidField("invalid_event", "events", fmt.Sprintf("invalid webhook event %q. Must be '*' or one of: %s", event, strings.Join(models.WebhookEvents, ", ")))
+		}
+		if event == models.EventAll {
+			return models.EventAll, nil
+		}
+		if !seen[event] {
+			seen[event] = true
+			normalized = append(normalized, event)
+		}
+	}
+	if len(normalized) == 0 {
+		return models.EventAll, nil
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// getWebhookForOwner loads a webhook of a workspace the user owns. Members
+// without the owner role cannot see the webhook or its secret.
+func (s *WebhookService) getWebhookForOwner(userID, id uint) (*models.WebhookSubscription, error) {
+	var webhook models.WebhookSubscription
+
+	if err := s.db.Where("workspace_id IN (?)", memberWorkspaceIDs(s.db, userID)).First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NotFound("webhook_not_found", "webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	role, err := getWorkspaceRole(s.db, webhook.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanManage() {
+		return nil, ErrInsufficientPermissions
+	}
+
+	return &webhook, nil
+}
+
+// Create Webhook. A signing secret is generated when none is given.
+func (s *WebhookService) CreateWebhook(userID uint, req models.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	rawURL, err := validateWebhookURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		token, err := generateToken()
+		if err != nil {
+			return nil, err
+		}
+		secret = WebhookSecretPrefix + token
+	}
+
+	// Default to the user's personal workspace
+	var workspaceID uint
+	if req.WorkspaceID != nil {
+		workspaceID = *req.WorkspaceID
+	} else {
+		id, err := personalWorkspaceID(s.db, userID)
+		if err != nil {
+			return nil, err
+		}
+		workspaceID = id
+	}
+
+	role, err := getWorkspaceRole(s.db, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrWorkspaceNotFound
+	}
+	if !role.CanManage() {
+		return nil, ErrInsufficientPermissions
+	}
+
+	webhook := models.WebhookSubscription{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		URL:         rawURL,
+		Events:      events,
+		Secret:      secret,
+		Active:      true,
+	}
+
+	if err := s.db.Create(&webhook).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// Get All Webhooks of the workspaces the user owns
+func (s *WebhookService) GetWebhooks(userID, workspaceID uint) ([]models.WebhookSubscription, error) {
+	var webhooks []models.WebhookSubscription
+
+	owned := s.db.Model(&models.WorkspaceMember{}).Select("workspace_id").
+		Where("user_id = ? AND role = ?", userID, models.WorkspaceRoleOwner)
+
+	query := s.db.Where("workspace_id IN (?)", owned)
+	if workspaceID != 0 {
+		query = query.Where("workspace_id = ?", workspaceID)
+	}
+
+	if err := query.Order("id ASC").Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// Get Webhook by ID
+func (s *WebhookService) GetWebhookByID(userID, id uint) (*models.WebhookSubscription, error) {
+	return s.getWebhookForOwner(userID, id)
+}
+
+// Update Webhook
+func (s *WebhookService) UpdateWebhook(userID, id uint, req models.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	webhook, err := s.getWebhookForOwner(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		rawURL, err := validateWebhookURL(*req.URL)
+		if err != nil {
+			return nil, err
+		}
+		webhook.URL = rawURL
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(*req.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = events
+	}
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			return nil, InvalidField("secret_required", "secret", "secret must not be empty")
+		}
+		webhook.Secret = *req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := s.db.Save(webhook).Error; err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// Delete Webhook. Its delivery log is deleted with it.
+func (s *WebhookService) DeleteWebhook(userID, id uint) error {
+	webhook, err := s.getWebhookForOwner(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", webhook.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		if err := tx.Delete(webhook).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get Webhook Deliveries, newest first
+func (s *WebhookService) GetDeliveries(userID, id uint, params models.PaginationParams) ([]models.WebhookDelivery, *models.Pagination, error) {
+	webhook, err := s.getWebhookForOwner(userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := s.db.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", webhook.ID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	limit := params.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	pagination := &models.Pagination{
+		CurrentPage: page,
+		PerPage:     limit,
+		Total:       total,
+		TotalPages:  totalPages,
+	}
+
+	return deliveries, pagination, nil
+}
+
+// webhookEvent is the JSON body posted to webhook subscribers
+type webhookEvent struct {
+	ID          string      `json:"id"`
+	Event       string      `json:"event"`
+	CreatedAt   time.Time   `json:"created_at"`
+	WorkspaceID uint        `json:"workspace_id"`
+	Data        interface{} `json:"data"`
+}
+
+// enqueueWebhookEvent writes one pending delivery per matching subscription.
+// It must be called with the transaction of the change that caused the event,
+// so the event is only sent if the change is committed.
+func enqueueWebhookEvent(db *gorm.DB, workspaceID uint, event string, data interface{}) error {
+	var subscriptions []models.WebhookSubscription
+	if err := db.Where("workspace_id = ? AND active = ?", workspaceID, true).Find(&subscriptions).Error; err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	var matching []models.WebhookSubscription
+	for _, subscription := range subscriptions {
+		if subscription.Matches(event) {
+			matching = append(matching, subscription)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return fmt.Errorf("failed to generate event id: %w", err)
+	}
+	eventID := "evt_" + hex.EncodeToString(random)
+
+	now := time.Now()
+	payload, err := json.Marshal(webhookEvent{
+		ID:          eventID,
+		Event:       event,
+		CreatedAt:   now,
+		WorkspaceID: workspaceID,
+		Data:        data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	deliveries := make([]models.WebhookDelivery, len(matching))
+	for i, subscription := range matching {
+		deliveries[i] = models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventID,
+			Event:          event,
+			Payload:        string(payload),
+			Status:         models.DeliveryStatusPending,
+			NextAttemptAt:  now,
+		}
+	}
+
+	if err := db.Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to enqueue webhook event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,503 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jayasaleh/todo-list/be/internal/models"
+)
+
+const personalWorkspaceName = "Personal"
+
+var (
+	errWorkspaceNameRequired = InvalidField("name_required", "name", "name is required")
+	errInvalidWorkspaceRole  = InvalidField("invalid_role", "role", "invalid role value. Must be 'owner', 'editor', or 'viewer'")
+	errMemberNotFound        = NotFound("member_not_found", "member not found")
+	errInvitationNotFound    = NotFound("invitation_not_found", "invitation not found")
+)
+
+type WorkspaceService struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceService(db *gorm.DB) *WorkspaceService {
+	return &WorkspaceService{
+		db: db,
+	}
+}
+
+// memberWorkspaceIDs returns a subquery selecting the active workspaces the user belongs to
+func memberWorkspaceIDs(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Table("workspace_members").
+		Select("workspace_members.workspace_id").
+		Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("workspace_members.user_id = ?", userID)
+}
+
+// getWorkspaceRole returns the role of the user in the workspace, or an empty role if not a member
+func getWorkspaceRole(db *gorm.DB, workspaceID, userID uint) (models.WorkspaceRole, error) {
+	var member models.WorkspaceMember
+
+	err := db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get workspace membership: %w", err)
+	}
+
+	return member.Role, nil
+}
+
+// requireWorkspaceWrite checks that the user may modify content in the workspace
+func requireWorkspaceWrite(db *gorm.DB, workspaceID, userID uint) error {
+	role, err := getWorkspaceRole(db, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanWrite() {
+		return ErrInsufficientPermissions
+	}
+	return nil
+}
+
+// personalWorkspaceID returns the first workspace owned by the user
+func personalWorkspaceID(db *gorm.DB, userID uint) (uint, error) {
+	var workspace models.Workspace
+
+	if err := db.Where("owner_id = ?", userID).Order("id").First(&workspace).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrWorkspaceNotFound
+		}
+		return 0, fmt.Errorf("failed to get personal workspace: %w", err)
+	}
+
+	return workspace.ID, nil
+}
+
+// createWorkspace creates a workspace with the user as its owner
+func createWorkspace(db *gorm.DB, name string, userID uint) (*models.Workspace, error) {
+	workspace := models.Workspace{
+		Name:    name,
+		OwnerID: &userID,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&workspace).Error; err != nil {
+			return err
+		}
+
+		member := models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      userID,
+			Role:        models.WorkspaceRoleOwner,
+		}
+		return tx.Create(&member).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+// getWorkspaceForMember loads a workspace the user belongs to along with their role
+func (s *WorkspaceService) getWorkspaceForMember(userID, id uint) (*models.Workspace, models.WorkspaceRole, error) {
+	var workspace models.Workspace
+
+	if err := s.db.First(&workspace, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrWorkspaceNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	role, err := getWorkspaceRole(s.db, workspace.ID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if role == "" {
+		return nil, "", ErrWorkspaceNotFound
+	}
+
+	return &workspace, role, nil
+}
+
+// Create Workspace
+func (s *WorkspaceService) CreateWorkspace(userID uint, req models.CreateWorkspaceRequest) (*models.Workspace, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errWorkspaceNameRequired
+	}
+
+	return createWorkspace(s.db, name, userID)
+}
+
+// Get Workspaces of a user with their role in each
+func (s *WorkspaceService) GetWorkspaces(userID uint) ([]models.Workspace, map[uint]models.WorkspaceRole, error) {
+	var members []models.WorkspaceMember
+
+	if err := s.db.Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get workspace memberships: %w", err)
+	}
+
+	roles := make(map[uint]models.WorkspaceRole, len(members))
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		roles[member.WorkspaceID] = member.Role
+		ids = append(ids, member.WorkspaceID)
+	}
+
+	var workspaces []models.Workspace
+	if len(ids) > 0 {
+		if err := s.db.Where("id IN ?", ids).Order("id").Find(&workspaces).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to get workspaces: %w", err)
+		}
+	}
+
+	return workspaces, roles, nil
+}
+
+// Get the IDs of the workspaces a user belongs to
+func (s *WorkspaceService) GetMemberWorkspaceIDs(userID uint) ([]uint, error) {
+	var ids []uint
+
+	if err := memberWorkspaceIDs(s.db, userID).Pluck("workspace_members.workspace_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get workspace memberships: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Get Workspace by ID
+func (s *WorkspaceService) GetWorkspaceByID(userID, id uint) (*models.Workspace, models.WorkspaceRole, error) {
+	return s.getWorkspaceForMember(userID, id)
+}
+
+// Update Workspace
+func (s *WorkspaceService) UpdateWorkspace(userID, id uint, req models.UpdateWorkspaceRequest) (*models.Workspace, models.WorkspaceRole, error) {
+	workspace, role, err := s.getWorkspaceForMember(userID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if !role.CanManage() {
+		return nil, "", ErrInsufficientPermissions
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, "", errWorkspaceNameRequired
+		}
+		workspace.Name = name
+	}
+
+	if err := s.db.Save(workspace).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	return workspace, role, nil
+}
+
+// Delete Workspace
+func (s *WorkspaceService) DeleteWorkspace(userID, id uint) error {
+	workspace, role, err := s.getWorkspaceForMember(userID, id)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() {
+		return ErrInsufficientPermissions
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Category{}).Where("workspace_id = ?", id).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check workspace usage: %w", err)
+	}
+	if count > 0 {
+		return Conflict("workspace_not_empty", "cannot delete workspace that still has categories")
+	}
+
+	if err := s.db.Delete(workspace).Error; err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+
+	return nil
+}
+
+// Get Workspace Members
+func (s *WorkspaceService) GetMembers(userID, id uint) ([]models.WorkspaceMember, error) {
+	if _, _, err := s.getWorkspaceForMember(userID, id); err != nil {
+		return nil, err
+	}
+
+	var members []models.WorkspaceMember
+	if err := s.db.Preload("User").Where("workspace_id = ?", id).Order("id").Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+
+	return members, nil
+}
+
+// Update Workspace Member role
+func (s *WorkspaceService) UpdateMember(userID, id, memberUserID uint, req models.UpdateWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	if !models.ValidateWorkspaceRole(req.Role) {
+		return nil, errInvalidWorkspaceRole
+	}
+
+	_, role, err := s.getWorkspaceForMember(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanManage() {
+		return nil, ErrInsufficientPermissions
+	}
+
+	var member models.WorkspaceMember
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("User").Where("workspace_id = ? AND user_id = ?", id, memberUserID).First(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errMemberNotFound
+			}
+			return fmt.Errorf("failed to get workspace member: %w", err)
+		}
+
+		if member.Role == models.WorkspaceRoleOwner && req.Role != models.WorkspaceRoleOwner {
+			if err := ensureAnotherOwner(tx, id, memberUserID); err != nil {
+				return err
+			}
+		}
+
+		member.Role = req.Role
+		if err := tx.Model(&member).Update("role", req.Role).Error; err != nil {
+			return fmt.Errorf("failed to update workspace member: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// Remove Workspace Member. Owners can remove anyone, members can remove themselves.
+func (s *WorkspaceService) RemoveMember(userID, id, memberUserID uint) error {
+	_, role, err := s.getWorkspaceForMember(userID, id)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() && userID != memberUserID {
+		return ErrInsufficientPermissions
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var member models.WorkspaceMember
+		if err := tx.Where("workspace_id = ? AND user_id = ?", id, memberUserID).First(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errMemberNotFound
+			}
+			return fmt.Errorf("failed to get workspace member: %w", err)
+		}
+
+		if member.Role == models.WorkspaceRoleOwner {
+			if err := ensureAnotherOwner(tx, id, memberUserID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Delete(&member).Error; err != nil {
+			return fmt.Errorf("failed to remove workspace member: %w", err)
+		}
+		return nil
+	})
+}
+
+// ensureAnotherOwner prevents a workspace from being left without an owner
+func ensureAnotherOwner(db *gorm.DB, workspaceID, exceptUserID uint) error {
+	var count int64
+
+	err := db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ? AND user_id <> ?", workspaceID, models.WorkspaceRoleOwner, exceptUserID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to count workspace owners: %w", err)
+	}
+	if count == 0 {
+		return Conflict("last_owner", "cannot remove the last owner of a workspace")
+	}
+
+	return nil
+}
+
+// Create Invitation
+func (s *WorkspaceService) CreateInvitation(userID, id uint, req models.CreateInvitationRequest) (*models.WorkspaceInvitation, error) {
+	if !models.ValidateWorkspaceRole(req.Role) {
+		return nil, errInvalidWorkspaceRole
+	}
+
+	_, role, err := s.getWorkspaceForMember(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanManage() {
+		return nil, ErrInsufficientPermissions
+	}
+
+	email := normalizeEmail(req.Email)
+
+	var count int64
+	err = s.db.Model(&models.WorkspaceMember{}).
+		Joins("JOIN users ON users.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ? AND users.email = ?", id, email).
+		Count(&count).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check workspace membership: %w", err)
+	}
+	if count > 0 {
+		return nil, Conflict("already_member", "user is already a member of this workspace")
+	}
+
+	err = s.db.Model(&models.WorkspaceInvitation{}).
+		Where("workspace_id = ? AND email = ? AND accepted_at IS NULL AND declined_at IS NULL", id, email).
+		Count(&count).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check invitations: %w", err)
+	}
+	if count > 0 {
+		return nil, Conflict("already_invited", "user has already been invited to this workspace")
+	}
+
+	invitation := models.WorkspaceInvitation{
+		WorkspaceID: id,
+		Email:       email,
+		Role:        req.Role,
+		InvitedByID: userID,
+	}
+
+	if err := s.db.Create(&invitation).Error; err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// Get pending Invitations of a workspace
+func (s *WorkspaceService) GetWorkspaceInvitations(userID, id uint) ([]models.WorkspaceInvitation, error) {
+	_, role, err := s.getWorkspaceForMember(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanManage() {
+		return nil, ErrInsufficientPermissions
+	}
+
+	var invitations []models.WorkspaceInvitation
+	err = s.db.Where("workspace_id = ? AND accepted_at IS NULL AND declined_at IS NULL", id).
+		Order("id").Find(&invitations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// Cancel Invitation
+func (s *WorkspaceService) CancelInvitation(userID, id, invitationID uint) error {
+	_, role, err := s.getWorkspaceForMember(userID, id)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() {
+		return ErrInsufficientPermissions
+	}
+
+	result := s.db.Where("id = ? AND workspace_id = ? AND accepted_at IS NULL AND declined_at IS NULL", invitationID, id).
+		Delete(&models.WorkspaceInvitation{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to cancel invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errInvitationNotFound
+	}
+
+	return nil
+}
+
+// Get pending Invitations addressed to the user's email
+func (s *WorkspaceService) GetMyInvitations(userID uint) ([]models.WorkspaceInvitation, error) {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var invitations []models.WorkspaceInvitation
+	err := s.db.Preload("Workspace").
+		Joins("JOIN workspaces ON workspaces.id = workspace_invitations.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("workspace_invitations.email = ? AND accepted_at IS NULL AND declined_at IS NULL", user.Email).
+		Order("workspace_invitations.id").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// Respond to an Invitation addressed to the user's email
+func (s *WorkspaceService) RespondInvitation(userID, invitationID uint, accept bool) (*models.WorkspaceInvitation, error) {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var invitation models.WorkspaceInvitation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Preload("Workspace").
+			Where("id = ? AND email = ? AND accepted_at IS NULL AND declined_at IS NULL", invitationID, user.Email).
+			First(&invitation).Error
+		if err != nil || invitation.Workspace == nil {
+			if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvitationNotFound
+			}
+			return fmt.Errorf("failed to get invitation: %w", err)
+		}
+
+		now := time.Now()
+		if !accept {
+			invitation.DeclinedAt = &now
+			return tx.Model(&invitation).Update("declined_at", now).Error
+		}
+
+		invitation.AcceptedAt = &now
+		if err := tx.Model(&invitation).Update("accepted_at", now).Error; err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+
+		role, err := getWorkspaceRole(tx, invitation.WorkspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if role != "" {
+			return nil
+		}
+
+		member := models.WorkspaceMember{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      userID,
+			Role:        invitation.Role,
+		}
+		if err := tx.Create(&member).Error; err != nil {
+			return fmt.Errorf("failed to add workspace member: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
@@ -0,0 +1,21 @@
+-- Rollback: Remove user accounts and ownership
+
+-- Step 1: Restore global category name uniqueness
+DROP INDEX IF EXISTS idx_categories_user_name;
+
+ALTER TABLE categories
+ADD CONSTRAINT categories_name_key UNIQUE (name);
+
+-- Step 2: Remove owner columns
+DROP INDEX IF EXISTS idx_todos_user_id;
+
+ALTER TABLE todos
+DROP COLUMN IF EXISTS user_id;
+
+ALTER TABLE categories
+DROP COLUMN IF EXISTS user_id;
+
+-- Step 3: Drop auth tables
+DROP TABLE IF EXISTS user_sessions;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,37 @@
+-- Rollback: Remove workspaces and return to per-user ownership
+
+-- Step 1: Restore user ownership foreign keys
+ALTER TABLE todos
+DROP CONSTRAINT IF EXISTS todos_user_id_fkey;
+
+ALTER TABLE todos
+ADD CONSTRAINT todos_user_id_fkey
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE categories
+DROP CONSTRAINT IF EXISTS categories_user_id_fkey;
+
+ALTER TABLE categories
+ADD CONSTRAINT categories_user_id_fkey
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+-- Step 2: Restore per-user category name uniqueness
+DROP INDEX IF EXISTS idx_categories_workspace_name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_user_name ON categories(user_id, name);
+
+-- Step 3: Remove workspace columns
+DROP INDEX IF EXISTS idx_todos_workspace_id;
+
+ALTER TABLE todos
+DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE categories
+DROP COLUMN IF EXISTS workspace_id;
+
+-- Step 4: Drop workspace tables
+DROP TABLE IF EXISTS workspace_invitations;
+
+DROP TABLE IF EXISTS workspace_members;
+
+DROP TABLE IF EXISTS workspaces;
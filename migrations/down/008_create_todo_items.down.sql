@@ -0,0 +1,8 @@
+-- Rollback: Remove checklist items from todos
+
+-- Step 1: Remove auto complete flag
+ALTER TABLE todos
+DROP COLUMN IF EXISTS auto_complete;
+
+-- Step 2: Drop todo_items table
+DROP TABLE IF EXISTS todo_items;
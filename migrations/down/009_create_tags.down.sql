@@ -0,0 +1,7 @@
+-- Rollback: Remove tags
+
+-- Step 1: Drop todo_tags join table
+DROP TABLE IF EXISTS todo_tags;
+
+-- Step 2: Drop tags table
+DROP TABLE IF EXISTS tags;
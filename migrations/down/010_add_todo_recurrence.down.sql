@@ -0,0 +1,11 @@
+-- Rollback: Remove recurring todos
+
+-- Step 1: Drop index
+DROP INDEX IF EXISTS idx_todos_recurrence_series_id;
+
+-- Step 2: Drop recurrence columns
+ALTER TABLE todos
+DROP COLUMN IF EXISTS recurrence_index,
+DROP COLUMN IF EXISTS recurrence_series_id,
+DROP COLUMN IF EXISTS recurrence_start,
+DROP COLUMN IF EXISTS recurrence_rule;
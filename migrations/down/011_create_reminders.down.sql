@@ -0,0 +1,4 @@
+-- Rollback: Remove due-date reminders
+
+-- Step 1: Drop reminders table
+DROP TABLE IF EXISTS reminders;
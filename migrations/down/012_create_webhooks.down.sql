@@ -0,0 +1,7 @@
+-- Rollback: Remove outgoing webhooks
+
+-- Step 1: Drop webhook_deliveries table
+DROP TABLE IF EXISTS webhook_deliveries;
+
+-- Step 2: Drop webhook_subscriptions table
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,4 @@
+-- Rollback: Remove real-time events
+
+-- Step 1: Drop event ID sequence
+DROP SEQUENCE IF EXISTS realtime_event_id_seq;
@@ -0,0 +1,7 @@
+-- Rollback: Remove full-text search on todos
+
+-- Step 1: Drop search index
+DROP INDEX IF EXISTS idx_todos_search_vector;
+
+-- Step 2: Drop search vector column
+ALTER TABLE todos DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,4 @@
+-- Rollback: Remove saved views
+
+-- Step 1: Drop saved_views table
+DROP TABLE IF EXISTS saved_views;
@@ -0,0 +1,14 @@
+-- Rollback: Remove the structured todo filters from saved views
+
+-- Step 1: Drop filter columns
+ALTER TABLE saved_views
+DROP COLUMN IF EXISTS category_ids,
+DROP COLUMN IF EXISTS priorities,
+DROP COLUMN IF EXISTS tags,
+DROP COLUMN IF EXISTS tag_mode,
+DROP COLUMN IF EXISTS completed,
+DROP COLUMN IF EXISTS overdue,
+DROP COLUMN IF EXISTS due_after,
+DROP COLUMN IF EXISTS due_before,
+DROP COLUMN IF EXISTS created_after,
+DROP COLUMN IF EXISTS created_before;
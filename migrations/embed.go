@@ -0,0 +1,28 @@
+// Package migrations embeds the SQL migration files, so that the binaries can
+// migrate the database without shipping the files.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/jayasaleh/todo-list/be/internal/config"
+)
+
+// FS contains the up and down directories of Postgres, and the same
+// directories of SQLite under sqlite/
+//
+//go:embed up/*.sql down/*.sql sqlite/up/*.sql sqlite/down/*.sql
+var FS embed.FS
+
+// ForDriver returns the up and down directories of a DB_DRIVER
+func ForDriver(driver string) (fs.FS, error) {
+	switch driver {
+	case config.DriverPostgres:
+		return FS, nil
+	case config.DriverSQLite:
+		return fs.Sub(FS, "sqlite")
+	}
+	return nil, fmt.Errorf("no migrations for DB_DRIVER %q", driver)
+}
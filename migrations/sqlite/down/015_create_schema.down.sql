@@ -0,0 +1,18 @@
+-- Rollback: Create the schema of version 15 for SQLite
+
+-- Step 1: Drop all tables, dependent tables first
+DROP TABLE IF EXISTS saved_views;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS reminders;
+DROP TABLE IF EXISTS todo_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS todo_items;
+DROP TABLE IF EXISTS todos;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS users;
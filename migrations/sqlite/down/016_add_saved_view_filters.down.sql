@@ -0,0 +1,13 @@
+-- Rollback: Remove the structured todo filters from saved views
+
+-- Step 1: Drop filter columns
+ALTER TABLE saved_views DROP COLUMN category_ids;
+ALTER TABLE saved_views DROP COLUMN priorities;
+ALTER TABLE saved_views DROP COLUMN tags;
+ALTER TABLE saved_views DROP COLUMN tag_mode;
+ALTER TABLE saved_views DROP COLUMN completed;
+ALTER TABLE saved_views DROP COLUMN overdue;
+ALTER TABLE saved_views DROP COLUMN due_after;
+ALTER TABLE saved_views DROP COLUMN due_before;
+ALTER TABLE saved_views DROP COLUMN created_after;
+ALTER TABLE saved_views DROP COLUMN created_before;
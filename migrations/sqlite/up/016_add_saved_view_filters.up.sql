@@ -0,0 +1,13 @@
+-- Add the structured todo filters to saved views
+
+-- Step 1: Add filter columns (lists are comma separated)
+ALTER TABLE saved_views ADD COLUMN category_ids TEXT NULL;
+ALTER TABLE saved_views ADD COLUMN priorities VARCHAR(50) NULL;
+ALTER TABLE saved_views ADD COLUMN tags TEXT NULL;
+ALTER TABLE saved_views ADD COLUMN tag_mode VARCHAR(10) NULL;
+ALTER TABLE saved_views ADD COLUMN completed BOOLEAN NULL;
+ALTER TABLE saved_views ADD COLUMN overdue BOOLEAN DEFAULT FALSE;
+ALTER TABLE saved_views ADD COLUMN due_after DATETIME NULL;
+ALTER TABLE saved_views ADD COLUMN due_before DATETIME NULL;
+ALTER TABLE saved_views ADD COLUMN created_after DATETIME NULL;
+ALTER TABLE saved_views ADD COLUMN created_before DATETIME NULL;
@@ -0,0 +1,39 @@
+-- Add user accounts and per-user ownership of categories and todos
+
+-- Step 1: Create users table
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+-- Step 2: Create user_sessions table (only token hashes are stored)
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
+
+-- Step 3: Add owner to categories and todos
+-- Existing rows keep a NULL owner and are no longer visible through the API
+ALTER TABLE categories
+ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE todos
+ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_id ON todos(user_id);
+
+-- Step 4: Category names are unique per user instead of globally
+ALTER TABLE categories
+DROP CONSTRAINT IF EXISTS categories_name_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_user_name ON categories(user_id, name);
@@ -0,0 +1,110 @@
+-- Add shared workspaces with role-based membership
+-- Categories and todos move from per-user ownership into workspaces
+
+-- Step 1: Create workspaces tables
+CREATE TABLE IF NOT EXISTS workspaces (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspaces_owner_id ON workspaces(owner_id);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_members_workspace_user ON workspace_members(workspace_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id ON workspace_members(user_id);
+
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invited_by_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    accepted_at TIMESTAMP NULL,
+    declined_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_invitations_workspace_id ON workspace_invitations(workspace_id);
+CREATE INDEX IF NOT EXISTS idx_workspace_invitations_email ON workspace_invitations(email);
+
+-- Step 2: Create a personal workspace for every existing user
+INSERT INTO workspaces (name, owner_id, created_at, updated_at)
+SELECT 'Personal', users.id, NOW(), NOW()
+FROM users
+WHERE NOT EXISTS (SELECT 1 FROM workspaces WHERE workspaces.owner_id = users.id);
+
+INSERT INTO workspace_members (workspace_id, user_id, role, created_at)
+SELECT workspaces.id, workspaces.owner_id, 'owner', NOW()
+FROM workspaces
+WHERE workspaces.owner_id IS NOT NULL
+AND NOT EXISTS (
+    SELECT 1 FROM workspace_members
+    WHERE workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = workspaces.owner_id
+);
+
+-- Step 3: Add workspace_id to categories and todos
+ALTER TABLE categories
+ADD COLUMN IF NOT EXISTS workspace_id INTEGER REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE todos
+ADD COLUMN IF NOT EXISTS workspace_id INTEGER REFERENCES workspaces(id) ON DELETE CASCADE;
+
+-- Step 4: Move owned categories into their owner's personal workspace
+UPDATE categories
+SET workspace_id = (
+    SELECT MIN(workspaces.id) FROM workspaces WHERE workspaces.owner_id = categories.user_id
+)
+WHERE workspace_id IS NULL AND user_id IS NOT NULL;
+
+-- Step 5: Move categories without an owner (created before user accounts) into a default workspace
+INSERT INTO workspaces (name, owner_id, created_at, updated_at)
+SELECT 'Default', NULL, NOW(), NOW()
+WHERE EXISTS (SELECT 1 FROM categories WHERE workspace_id IS NULL);
+
+UPDATE categories
+SET workspace_id = (SELECT MAX(id) FROM workspaces WHERE name = 'Default' AND owner_id IS NULL)
+WHERE workspace_id IS NULL;
+
+-- Step 6: Todos live in the workspace of their category
+UPDATE todos
+SET workspace_id = (SELECT categories.workspace_id FROM categories WHERE categories.id = todos.category_id)
+WHERE workspace_id IS NULL;
+
+-- Step 7: Require a workspace and make category names unique per workspace
+ALTER TABLE categories
+ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE todos
+ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todos_workspace_id ON todos(workspace_id);
+
+DROP INDEX IF EXISTS idx_categories_user_name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_workspace_name ON categories(workspace_id, name);
+
+-- Step 8: The creator of a category or todo no longer owns it
+ALTER TABLE categories
+DROP CONSTRAINT IF EXISTS categories_user_id_fkey;
+
+ALTER TABLE categories
+ADD CONSTRAINT categories_user_id_fkey
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE todos
+DROP CONSTRAINT IF EXISTS todos_user_id_fkey;
+
+ALTER TABLE todos
+ADD CONSTRAINT todos_user_id_fkey
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
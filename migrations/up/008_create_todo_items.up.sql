@@ -0,0 +1,18 @@
+-- Add checklist items (subtasks) to todos
+
+-- Step 1: Create todo_items table
+CREATE TABLE IF NOT EXISTS todo_items (
+    id SERIAL PRIMARY KEY,
+    todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    completed BOOLEAN DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_items_todo_id ON todo_items(todo_id);
+
+-- Step 2: Allow todos to complete automatically when all items are checked
+ALTER TABLE todos
+ADD COLUMN IF NOT EXISTS auto_complete BOOLEAN DEFAULT FALSE;
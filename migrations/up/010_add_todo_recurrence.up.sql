@@ -0,0 +1,11 @@
+-- Add recurring todos (RRULE-style schedules)
+
+-- Step 1: Add recurrence columns
+ALTER TABLE todos
+ADD COLUMN IF NOT EXISTS recurrence_rule VARCHAR(255) NULL,
+ADD COLUMN IF NOT EXISTS recurrence_start TIMESTAMP NULL,
+ADD COLUMN IF NOT EXISTS recurrence_series_id INTEGER NULL,
+ADD COLUMN IF NOT EXISTS recurrence_index INTEGER DEFAULT 0;
+
+-- Step 2: Add index for looking up occurrences of a series
+CREATE INDEX IF NOT EXISTS idx_todos_recurrence_series_id ON todos(recurrence_series_id);
@@ -0,0 +1,20 @@
+-- Add due-date reminders
+
+-- Step 1: Create reminders table
+CREATE TABLE IF NOT EXISTS reminders (
+    id SERIAL PRIMARY KEY,
+    todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    offset_minutes INTEGER NOT NULL,
+    remind_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NULL,
+    locked_until TIMESTAMP NULL,
+    attempts INTEGER DEFAULT 0,
+    last_error TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminders_todo_offset ON reminders(todo_id, offset_minutes);
+
+-- Step 2: Add index for the scheduler, which only looks at unsent reminders
+CREATE INDEX IF NOT EXISTS idx_reminders_remind_at ON reminders(remind_at) WHERE sent_at IS NULL;
@@ -0,0 +1,4 @@
+-- Add real-time events (Server-Sent Events)
+
+-- Step 1: Create sequence for event IDs, shared by every server instance
+CREATE SEQUENCE IF NOT EXISTS realtime_event_id_seq;
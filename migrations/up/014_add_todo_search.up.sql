@@ -0,0 +1,12 @@
+-- Add full-text search on todo title and description
+
+-- Step 1: Add generated search vector (title ranks higher than description)
+ALTER TABLE todos
+ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+
+-- Step 2: Add GIN index for search queries
+CREATE INDEX IF NOT EXISTS idx_todos_search_vector ON todos USING GIN (search_vector);
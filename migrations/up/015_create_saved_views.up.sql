@@ -0,0 +1,20 @@
+-- Add saved views (named todo list queries)
+
+-- Step 1: Create saved_views table
+CREATE TABLE IF NOT EXISTS saved_views (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    workspace_id INTEGER NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    search VARCHAR(255) NULL,
+    query VARCHAR(500) NULL,
+    sort VARCHAR(255) NULL,
+    pagination VARCHAR(20) DEFAULT 'offset',
+    "limit" INTEGER DEFAULT 10,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Step 2: View names are unique per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_views_user_name ON saved_views(user_id, name);
+CREATE INDEX IF NOT EXISTS idx_saved_views_workspace_id ON saved_views(workspace_id);
@@ -0,0 +1,14 @@
+-- Add the structured todo filters to saved views
+
+-- Step 1: Add filter columns (lists are comma separated)
+ALTER TABLE saved_views
+ADD COLUMN IF NOT EXISTS category_ids TEXT NULL,
+ADD COLUMN IF NOT EXISTS priorities VARCHAR(50) NULL,
+ADD COLUMN IF NOT EXISTS tags TEXT NULL,
+ADD COLUMN IF NOT EXISTS tag_mode VARCHAR(10) NULL,
+ADD COLUMN IF NOT EXISTS completed BOOLEAN NULL,
+ADD COLUMN IF NOT EXISTS overdue BOOLEAN DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS due_after TIMESTAMP NULL,
+ADD COLUMN IF NOT EXISTS due_before TIMESTAMP NULL,
+ADD COLUMN IF NOT EXISTS created_after TIMESTAMP NULL,
+ADD COLUMN IF NOT EXISTS created_before TIMESTAMP NULL;
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// FieldError describes why a request field is invalid. Code is a
+// machine-readable name of the problem, e.g. "required" or "invalid_time".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// statusErrorCode returns the error code of responses that are not built
+// from a domain error
+func statusErrorCode(code int) string {
+	switch code {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusInternalServerError:
+		return "internal_error"
+	}
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(code), " ", "_"))
+}
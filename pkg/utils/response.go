@@ -17,6 +17,12 @@ type ErrorResponse struct {
 	Code    int    `json:"code"`
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// ErrorCode is a machine-readable name of the error, e.g. "todo_not_found"
+	ErrorCode string       `json:"error_code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	// Data describes what happened before the request failed, e.g. the
+	// results of a rolled back bulk operation
+	Data interface{} `json:"data,omitempty"`
 }
 
 // SuccessResponse - Return success response
@@ -31,11 +37,17 @@ func SuccessResponseJSON(c *gin.Context, code int, message string, data interfac
 
 // ErrorResponseJSON - Return error response
 func ErrorResponseJSON(c *gin.Context, code int, message string) {
-	c.JSON(code, ErrorResponse{
-		Code:    code,
-		Status:  "error",
-		Message: message,
-	})
+	c.JSON(code, NewErrorResponse(code, message))
+}
+
+// NewErrorResponse returns an error response with the error code of the status
+func NewErrorResponse(code int, message string) ErrorResponse {
+	return ErrorResponse{
+		Code:      code,
+		Status:    "error",
+		Message:   message,
+		ErrorCode: statusErrorCode(code),
+	}
 }
 
 // BadRequest - 400 Bad Request
@@ -43,11 +55,26 @@ func BadRequest(c *gin.Context, message string) {
 	ErrorResponseJSON(c, http.StatusBadRequest, message)
 }
 
+// Unauthorized - 401 Unauthorized
+func Unauthorized(c *gin.Context, message string) {
+	ErrorResponseJSON(c, http.StatusUnauthorized, message)
+}
+
+// Forbidden - 403 Forbidden
+func Forbidden(c *gin.Context, message string) {
+	ErrorResponseJSON(c, http.StatusForbidden, message)
+}
+
 // NotFound - 404 Not Found
 func NotFound(c *gin.Context, message string) {
 	ErrorResponseJSON(c, http.StatusNotFound, message)
 }
 
+// Conflict - 409 Conflict
+func Conflict(c *gin.Context, message string) {
+	ErrorResponseJSON(c, http.StatusConflict, message)
+}
+
 // InternalServerError - 500 Internal Server Error
 func InternalServerError(c *gin.Context, message string) {
 	ErrorResponseJSON(c, http.StatusInternalServerError, message)
@@ -74,4 +101,3 @@ func PaginatedResponse(c *gin.Context, message string, data interface{}, paginat
 	}
 	c.JSON(http.StatusOK, response)
 }
-